@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
 
 	"savvyshopper/runner"
 )
 
 func main() {
-	if err := runner.Run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := runner.Run(ctx, os.Args[1:], os.Stdout); err != nil {
+		stop()
 		os.Exit(1)
 	}
 }
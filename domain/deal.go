@@ -0,0 +1,20 @@
+package domain
+
+// Advice says whether to buy an offer now or wait for a better price.
+type Advice string
+
+const (
+	AdviceBuy  Advice = "buy"
+	AdviceWait Advice = "wait"
+)
+
+// Deal rates how good a buy an offer is.
+type Deal struct {
+	// Score runs from 0 (poor) to 100 (excellent).
+	Score int
+	// Advice is empty until the offer is scored.
+	Advice Advice
+	// Reason justifies Advice in a short phrase, such as "at its 90-day
+	// low".
+	Reason string
+}
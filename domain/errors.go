@@ -3,7 +3,9 @@ package domain
 import "errors"
 
 var (
-    ErrNetwork   = errors.New("network error")
-    ErrAuth      = errors.New("authentication error")
-    ErrNoResults = errors.New("no offers found")
+    ErrNetwork    = errors.New("network error")
+    ErrAuth       = errors.New("authentication error")
+    ErrNoResults  = errors.New("no offers found")
+    ErrNotFound   = errors.New("product not found")
+    ErrInvalidRef = errors.New("unrecognized product reference")
 )
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Retailers on marketplaces outside the US. Amazon and Walmart are the US
+// sites.
+const (
+	AmazonUK  Retailer = "Amazon UK"
+	AmazonDE  Retailer = "Amazon DE"
+	AmazonJP  Retailer = "Amazon JP"
+	WalmartCA Retailer = "Walmart CA"
+)
+
+// Marketplace describes the site a Retailer sells on.
+type Marketplace struct {
+	Retailer Retailer
+	// Brand is the company behind the site: Amazon or Walmart.
+	Brand Retailer
+	// Domain is the site's host name, e.g. amazon.co.uk.
+	Domain string
+	// Currency is the ISO 4217 code prices on the site are in.
+	Currency string
+	// Locale is the BCP 47 tag prices are formatted for.
+	Locale string
+	// Zinc is Zinc's code for the site, e.g. amazon_uk.
+	Zinc string
+}
+
+// marketplaces lists the supported sites, US first.
+var marketplaces = []Marketplace{
+	{Retailer: Amazon, Brand: Amazon, Domain: "amazon.com", Currency: "USD", Locale: "en-US", Zinc: "amazon"},
+	{Retailer: AmazonUK, Brand: Amazon, Domain: "amazon.co.uk", Currency: "GBP", Locale: "en-GB", Zinc: "amazon_uk"},
+	{Retailer: AmazonDE, Brand: Amazon, Domain: "amazon.de", Currency: "EUR", Locale: "de-DE", Zinc: "amazon_de"},
+	{Retailer: AmazonJP, Brand: Amazon, Domain: "amazon.co.jp", Currency: "JPY", Locale: "ja-JP", Zinc: "amazon_jp"},
+	{Retailer: Walmart, Brand: Walmart, Domain: "walmart.com", Currency: "USD", Locale: "en-US", Zinc: "walmart"},
+	{Retailer: WalmartCA, Brand: Walmart, Domain: "walmart.ca", Currency: "CAD", Locale: "en-CA", Zinc: "walmart_ca"},
+}
+
+// Marketplaces returns every supported site.
+func Marketplaces() []Marketplace {
+	return append([]Marketplace(nil), marketplaces...)
+}
+
+// DefaultMarketplaces are the sites searched unless others are chosen.
+var DefaultMarketplaces = []Retailer{Amazon, Walmart}
+
+// Marketplace returns the site r sells on. An unknown retailer is treated as
+// a US site of its own brand.
+func (r Retailer) Marketplace() Marketplace {
+	for _, m := range marketplaces {
+		if m.Retailer == r {
+			return m
+		}
+	}
+	return Marketplace{Retailer: r, Brand: r, Domain: strings.ToLower(string(r)) + ".com", Currency: "USD", Locale: "en-US", Zinc: strings.ToLower(string(r))}
+}
+
+// ParseMarketplace finds a site by domain (amazon.co.uk), retailer name
+// (Amazon UK) or Zinc code (amazon_uk), ignoring case.
+func ParseMarketplace(s string) (Retailer, error) {
+	s = strings.TrimSpace(s)
+	for _, m := range marketplaces {
+		if strings.EqualFold(s, m.Domain) || strings.EqualFold(s, string(m.Retailer)) || strings.EqualFold(s, m.Zinc) {
+			return m.Retailer, nil
+		}
+	}
+	names := make([]string, len(marketplaces))
+	for i, m := range marketplaces {
+		names[i] = m.Domain
+	}
+	return "", fmt.Errorf("invalid marketplace %q: want one of %s", s, strings.Join(names, ", "))
+}
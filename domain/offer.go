@@ -8,9 +8,105 @@ const (
     Walmart Retailer = "Walmart"
 )
 
+// Fulfillment says who ships an offer.
+type Fulfillment string
+
+const (
+    // FulfilledByRetailer means the retailer ships the item, whether it sells
+    // it or stocks it for a marketplace seller (e.g. Fulfilled by Amazon).
+    FulfilledByRetailer Fulfillment = "retailer"
+    // FulfilledBySeller means the marketplace seller ships the item itself.
+    FulfilledBySeller Fulfillment = "seller"
+)
+
+// Condition is the item condition of an offer.
+type Condition string
+
+const (
+    ConditionNew         Condition = "new"
+    ConditionUsed        Condition = "used"
+    ConditionRefurbished Condition = "refurbished"
+)
+
 type Offer struct {
     Title    string
     Price    float64
     URL      string
     Retailer Retailer
+
+    // ListPrice is the retailer's reference price before any sale; zero
+    // when the offer is not on sale.
+    ListPrice float64
+    // Promotions lists the coupons, subscription discounts and member
+    // prices available on the offer.
+    Promotions []Promotion
+    // Savings is what the buyer's applicable promotions take off Price; zero
+    // until worked out for their memberships.
+    Savings float64
+
+    // ProductID is the retailer's own identifier: an ASIN on Amazon, an
+    // item ID on Walmart.
+    ProductID string
+    // Seller is the merchant behind the offer when it is not the retailer.
+    Seller string
+    // SellerRating is the seller's percentage of positive feedback, 0-100;
+    // zero when unknown.
+    SellerRating float64
+    // SellerRatings is the number of feedback ratings the seller has.
+    SellerRatings int
+    // Fulfillment is empty when the retailer does not say.
+    Fulfillment Fulfillment
+    // GTIN is the UPC/EAN/GTIN barcode when the retailer exposes one.
+    GTIN string
+    // Model is the manufacturer model or part number when known.
+    Model string
+
+    // Shipping is the shipping charge; zero when free or unknown.
+    Shipping float64
+    // Tax is the estimated sales tax charged at checkout; zero until
+    // estimated for a delivery ZIP code.
+    Tax float64
+    // Rating is the average star rating from 0 to 5; zero when unrated.
+    Rating float64
+    // Reviews is the number of customer reviews.
+    Reviews int
+    // Condition is empty when the retailer does not say, which in practice
+    // means new.
+    Condition  Condition
+    Prime      bool
+    OutOfStock bool
+
+    // Relevance rates how well the offer matches the search query, from 0
+    // (off-target) to 1. It is zero until scored.
+    Relevance float64
+    // Deal rates the offer against its list price and price history; zero
+    // until scored.
+    Deal Deal
+}
+
+// EffectivePrice returns what the buyer pays for the item after Savings.
+func (o Offer) EffectivePrice() float64 {
+    return o.Price - o.Savings
+}
+
+// Discount returns the fraction the effective price is below the list price,
+// or below Price when there is no list price; zero when not discounted.
+func (o Offer) Discount() float64 {
+    list := max(o.ListPrice, o.Price)
+    if list <= 0 {
+        return 0
+    }
+    return 1 - o.EffectivePrice()/list
+}
+
+// Landed returns the cost of the offer delivered: effective price plus
+// shipping plus estimated tax.
+func (o Offer) Landed() float64 {
+    return o.EffectivePrice() + o.Shipping + o.Tax
+}
+
+// Currency returns the ISO 4217 code of the offer's prices, which are in the
+// local currency of its marketplace.
+func (o Offer) Currency() string {
+    return o.Retailer.Marketplace().Currency
 }
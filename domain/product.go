@@ -0,0 +1,47 @@
+package domain
+
+// ProductDetails is the full description of a single retailer listing, as
+// opposed to the summary carried by an Offer.
+type ProductDetails struct {
+	Retailer    Retailer
+	ProductID   string
+	Title       string
+	Brand       string
+	Description string
+	URL         string
+	Price       float64
+	Rating      float64
+	Reviews     int
+	GTIN        string
+	Model       string
+	OutOfStock  bool
+	Images      []string
+	Variants    []Variant
+	// Offers holds every seller's offer for the listing.
+	Offers []Offer
+}
+
+// Variant is a sibling listing that differs in some dimension, such as
+// color or size.
+type Variant struct {
+	ProductID string
+	// Specifics maps a dimension to its value, e.g. "Color" to "Black".
+	Specifics map[string]string
+}
+
+// Offer returns the listing itself as an Offer, for matching against search
+// results from other retailers.
+func (p ProductDetails) Offer() Offer {
+	return Offer{
+		Title:      p.Title,
+		Price:      p.Price,
+		URL:        p.URL,
+		Retailer:   p.Retailer,
+		ProductID:  p.ProductID,
+		GTIN:       p.GTIN,
+		Model:      p.Model,
+		Rating:     p.Rating,
+		Reviews:    p.Reviews,
+		OutOfStock: p.OutOfStock,
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+// PromotionKind is the kind of discount a Promotion offers.
+type PromotionKind string
+
+const (
+	// PromotionCoupon is a coupon anyone can clip.
+	PromotionCoupon PromotionKind = "coupon"
+	// PromotionSubscribeSave is a discount for ordering on a recurring
+	// subscription, such as Amazon Subscribe & Save.
+	PromotionSubscribeSave PromotionKind = "subscribe_save"
+	// PromotionMember is a price reserved for members of a program.
+	PromotionMember PromotionKind = "member"
+)
+
+// Membership is a paid shopping program that unlocks member pricing.
+type Membership string
+
+const (
+	MembershipPrime       Membership = "prime"
+	MembershipWalmartPlus Membership = "walmart_plus"
+)
+
+// Promotion is a discount available on an offer. Exactly one of AmountOff
+// and PercentOff is set.
+type Promotion struct {
+	Kind        PromotionKind
+	Description string
+	// AmountOff is taken off the price, in the offer's currency.
+	AmountOff float64
+	// PercentOff is taken off the price, from 0 to 100.
+	PercentOff float64
+	// Membership is the program a PromotionMember is reserved for.
+	Membership Membership
+}
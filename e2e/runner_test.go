@@ -2,15 +2,38 @@ package e2e
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"savvyshopper/domain"
+	"savvyshopper/internal/fakezinc"
+	"savvyshopper/internal/history"
 	"savvyshopper/internal/price"
 	"savvyshopper/runner"
 )
 
+// TestMain keeps the price history the runs record out of the user's own.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "savvyshopper-e2e")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("SAVVY_HISTORY", filepath.Join(dir, "history.jsonl"))
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // TestRunnerEndToEnd verifies the runner works with mock searchers.
 func TestRunnerEndToEnd(t *testing.T) {
 	// Set mock API key
@@ -68,3 +91,889 @@ func (m *mockSearcher) Search(ctx context.Context, query string) ([]domain.Offer
 		},
 	}, nil
 }
+
+// TestRunnerCompare verifies the compare view shows one row per product.
+func TestRunnerCompare(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+
+	var buf strings.Builder
+	err := runner.Run(context.Background(), []string{"compare", "test query"}, &buf, mockSearchers)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 product rows, got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[0], "Amazon") || !strings.Contains(lines[0], "Walmart") {
+		t.Errorf("header does not contain both retailer names: %q", lines[0])
+	}
+}
+
+// TestRunnerSearchSubcommandNames verifies a query that names a subcommand
+// can still be searched for, with search or --.
+func TestRunnerSearchSubcommandNames(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{retailer: domain.Amazon}}
+	tmpl := "{{.Query}}: {{len .Offers}} offers"
+	for _, args := range [][]string{
+		{"search", "--no-history", "--template", tmpl, "watch"},
+		{"--no-history", "--template", tmpl, "--", "watch"},
+		{"search", "--no-history", "--template", tmpl, "search"},
+	} {
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), args, &buf, searchers); err != nil {
+			t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+		}
+		if want := args[len(args)-1] + ": 3 offers"; buf.String() != want {
+			t.Errorf("Run(%q) output = %q, want %q", args, buf.String(), want)
+		}
+	}
+}
+
+// TestRunnerExclude verifies --exclude drops offers without a relevance
+// threshold.
+func TestRunnerExclude(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{retailer: domain.Amazon}}
+
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--exclude", "product 3", "Test Product"}, &buf, searchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, "Test Product 3") || !strings.Contains(out, "Test Product 1") {
+		t.Errorf("--exclude without --min-relevance output:\n%s", out)
+	}
+}
+
+// TestRunnerRelevanceFlags verifies --exclude, --min-relevance and --verbose.
+func TestRunnerRelevanceFlags(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+
+	var buf strings.Builder
+	args := []string{"Test Product", "--exclude", "product 3", "--min-relevance", "0.5", "-v"}
+	if err := runner.Run(context.Background(), args, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Relevance") {
+		t.Errorf("verbose output missing Relevance column:\n%s", output)
+	}
+	if strings.Contains(output, "Test Product 3") {
+		t.Errorf("excluded offer still shown:\n%s", output)
+	}
+}
+
+// TestRunnerSortAndFilter verifies --sort, --desc and --max-price.
+func TestRunnerSortAndFilter(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+
+	var buf strings.Builder
+	args := []string{"--sort", "retailer", "--desc", "--max-price", "30", "test query"}
+	if err := runner.Run(context.Background(), args, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header and 4 rows under $30, got:\n%s", buf.String())
+	}
+	want := []string{"Test Product 1", "Test Product 2", "Test Product 1", "Test Product 2"}
+	retailers := []string{"Walmart", "Walmart", "Amazon", "Amazon"}
+	for i, line := range lines[1:] {
+		if !strings.HasPrefix(line, want[i]) || !strings.Contains(line, retailers[i]) {
+			t.Errorf("row %d = %q, want %s from %s", i, line, want[i], retailers[i])
+		}
+	}
+
+	if err := runner.Run(context.Background(), []string{"--sort", "cheapest", "q"}, &buf, mockSearchers); err == nil {
+		t.Errorf("expected error for unknown sort key")
+	}
+}
+
+// TestRunnerZIPTax verifies --zip adds tax and landed cost columns.
+func TestRunnerZIPTax(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon: &mockSearcher{retailer: domain.Amazon},
+	}
+
+	var buf strings.Builder
+	args := []string{"--zip", "94103", "--sort", "landed", "--max-price", "20", "test query"}
+	if err := runner.Run(context.Background(), args, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, buf.String())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "Landed") {
+		t.Fatalf("expected header with Landed and one row, got:\n%s", buf.String())
+	}
+	// 19.99 at San Francisco's 8.625%
+	if !strings.Contains(lines[1], "$1.72") || !strings.Contains(lines[1], "$21.71") {
+		t.Errorf("row = %q, want tax $1.72 and landed $21.71", lines[1])
+	}
+
+	for _, args := range [][]string{{"--zip", "9410", "q"}, {"--tax-rates", "rates.json", "q"}} {
+		if err := runner.Run(context.Background(), args, io.Discard, mockSearchers); err == nil {
+			t.Errorf("Run(%q) error = nil, want invalid flag error", args)
+		}
+	}
+}
+
+// promoSearcher returns one offer on sale with a coupon and a Prime price.
+type promoSearcher struct{}
+
+func (promoSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	return []domain.Offer{{
+		Title:     "Test Product 1",
+		Price:     40,
+		ListPrice: 50,
+		URL:       "https://example.com/1",
+		Retailer:  domain.Amazon,
+		Promotions: []domain.Promotion{
+			{Kind: domain.PromotionCoupon, AmountOff: 5},
+			{Kind: domain.PromotionMember, Membership: domain.MembershipPrime, AmountOff: 5},
+		},
+	}}, nil
+}
+
+// TestRunnerMemberPricing verifies --member lowers the price shown and the
+// discount against the list price.
+func TestRunnerMemberPricing(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: promoSearcher{}}
+
+	tests := []struct {
+		args  []string
+		price string
+		off   string
+	}{
+		{[]string{"test query"}, "$35.00", "-30%"},
+		{[]string{"--member", "prime", "test query"}, "$30.00", "-40%"},
+	}
+	for _, tt := range tests {
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), tt.args, &buf, searchers); err != nil {
+			t.Fatalf("Run(%q) failed: %v", tt.args, err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if got := strings.Fields(lines[0]); !slices.Equal(got[:5], []string{"Title", "Price", "Retailer", "List", "Off"}) {
+			t.Errorf("Run(%q) header = %q", tt.args, lines[0])
+		}
+		if cols := strings.Fields(lines[1]); !slices.Contains(cols, tt.price) || !slices.Contains(cols, "$50.00") || !slices.Contains(cols, tt.off) {
+			t.Errorf("Run(%q) row = %q, want price %s, list $50.00 and %s", tt.args, lines[1], tt.price, tt.off)
+		}
+	}
+
+	if err := runner.Run(context.Background(), []string{"--member", "costco", "q"}, io.Discard, searchers); err == nil {
+		t.Error("Run(--member costco) error = nil, want invalid membership")
+	}
+}
+
+// priceSearcher returns one listing at a price the test changes between runs.
+type priceSearcher struct {
+	price float64
+}
+
+func (p *priceSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	return []domain.Offer{{Title: "Test Product 1", Price: p.price, ProductID: "B000TEST01", URL: "https://example.com/1", Retailer: domain.Amazon}}, nil
+}
+
+// failingSearcher always fails with err.
+type failingSearcher struct {
+	err error
+}
+
+func (f *failingSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	return nil, f.err
+}
+
+// TestRunnerFormats verifies search results can be written as Markdown and
+// HTML.
+func TestRunnerFormats(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), append(args, "test query"), &buf, searchers); err != nil {
+			t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+		}
+		return buf.String()
+	}
+
+	md := run("--format", "md")
+	if !strings.HasPrefix(md, "| Title | Price | Retailer |") || !strings.Contains(md, "[Test Product 1](https://example.com/1) | **$19.99** | `Amazon`") {
+		t.Errorf("markdown output = %q", md)
+	}
+	html := run("--format", "html")
+	if !strings.Contains(html, "<title>Prices for test query</title>") || !strings.Contains(html, `<td class="price best">$19.99</td>`) {
+		t.Errorf("html output = %q", html)
+	}
+
+	tmpl := filepath.Join(t.TempDir(), "brand.html")
+	if err := os.WriteFile(tmpl, []byte(`{{define "footer"}}<p>Sent by Acme</p>{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out := run("--format", "html", "--html-template", tmpl); !strings.Contains(out, "<p>Sent by Acme</p>") {
+		t.Errorf("branded html output = %q", out)
+	}
+
+	for _, args := range [][]string{{"--format", "pdf"}, {"--format", "md", "--stream"}, {"--html-template", tmpl}, {"compare", "--format", "md"}} {
+		if err := runner.Run(context.Background(), append(args, "test query"), io.Discard, searchers); err == nil {
+			t.Errorf("Run(%q) error = nil, want error", args)
+		}
+	}
+}
+
+// TestRunnerURLs verifies --urls shortens or hides offer URLs, and that
+// output to a non-terminal has no colors.
+func TestRunnerURLs(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{retailer: domain.Amazon}}
+	for mode, want := range map[string]string{"short": "example.com/1", "none": ""} {
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), []string{"--urls", mode, "test query"}, &buf, searchers); err != nil {
+			t.Fatalf("Run(--urls %s) failed: %v", mode, err)
+		}
+		out := buf.String()
+		if strings.Contains(out, "https://") || !strings.Contains(out, want) || strings.Contains(out, "\033[") {
+			t.Errorf("Run(--urls %s) output = %q", mode, out)
+		}
+	}
+	if err := runner.Run(context.Background(), []string{"--urls", "tiny", "test query"}, io.Discard, searchers); err == nil {
+		t.Error("Run(--urls tiny) error = nil, want error")
+	}
+}
+
+// TestRunnerTemplate verifies search results can be written with a
+// user-defined template.
+func TestRunnerTemplate(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &failingSearcher{err: domain.ErrNetwork},
+	}
+
+	var buf strings.Builder
+	text := `{{.Query}}:{{range .Offers}} {{.Retailer}} {{price .}}{{end}}{{range .Retailers}}; {{.Retailer}} {{.Status}}{{end}}`
+	if err := runner.Run(context.Background(), []string{"--template", text, "--max-price", "30", "test query"}, &buf, searchers); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if want := "test query: Amazon $19.99 Amazon $29.99; Amazon ok; Walmart failed"; buf.String() != want {
+		t.Errorf("Run(--template) = %q, want %q", buf.String(), want)
+	}
+
+	file := filepath.Join(t.TempDir(), "offers.tmpl")
+	if err := os.WriteFile(file, []byte("{{range .Offers}}{{.URL}}\n{{end}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := runner.Run(context.Background(), []string{"--template-file", file, "test query"}, &buf, searchers); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if want := "https://example.com/1\nhttps://example.com/2\nhttps://example.com/3\n"; buf.String() != want {
+		t.Errorf("Run(--template-file) = %q, want %q", buf.String(), want)
+	}
+
+	for _, args := range [][]string{{"--template", "{{.Nope"}, {"--template", "x", "--template-file", file}, {"--template", "x", "--format", "md"}, {"--template", "x", "--stream"}, {"compare", "--template", "x"}} {
+		if err := runner.Run(context.Background(), append(args, "test query"), io.Discard, searchers); err == nil {
+			t.Errorf("Run(%q) error = nil, want error", args)
+		}
+	}
+}
+
+// TestRunnerDeals verifies deals are rated against the prices recorded by
+// earlier searches.
+func TestRunnerDeals(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	searcher := &priceSearcher{}
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: searcher}
+
+	tests := []struct {
+		price  float64
+		advice string
+	}{
+		{50, "wait: not discounted and no price history yet"},
+		{40, "buy now: at its 90-day low"},
+		{48, "wait: 20% above its 30-day low"},
+	}
+	for _, tt := range tests {
+		searcher.price = tt.price
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), []string{"--history", historyFile, "--sort", "deal", "test query"}, &buf, searchers); err != nil {
+			t.Fatalf("Run() at %v failed: %v", tt.price, err)
+		}
+		if got := buf.String(); !strings.Contains(got, "Advice") || !strings.Contains(got, tt.advice) {
+			t.Errorf("Run() at %v output = %q, want advice %q", tt.price, got, tt.advice)
+		}
+	}
+
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != len(tests) {
+		t.Errorf("history has %d lines, want %d", lines, len(tests))
+	}
+
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--no-history", "--deals", "test query"}, &buf, searchers); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "no price history yet") {
+		t.Errorf("Run(--no-history) output = %q, want no history used", buf.String())
+	}
+}
+
+// TestRunnerWatchForecast verifies watched listings are looked up, recorded
+// and forecast from their history.
+func TestRunnerWatchForecast(t *testing.T) {
+	zinc := httptest.NewServer(fakezinc.New(fakezinc.DefaultCatalog()))
+	defer zinc.Close()
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("ZINC_BASE_URL", zinc.URL)
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	run := func(args ...string) string {
+		t.Helper()
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), append(args, "--history", historyFile), &buf); err != nil {
+			t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+		}
+		return buf.String()
+	}
+
+	if out := run("watch", "add", "https://www.amazon.com/dp/B0D1XD1ZV3"); !strings.Contains(out, "Watching 1:") {
+		t.Errorf("watch add output = %q", out)
+	}
+	run("watch", "refresh")
+	if out := run("watch", "list"); !strings.Contains(out, "B0D1XD1ZV3") || !strings.Contains(out, "$189.99") {
+		t.Errorf("watch list output = %q", out)
+	}
+	if err := runner.Run(context.Background(), []string{"forecast", "1", "--history", historyFile}, io.Discard); err == nil {
+		t.Error("forecast with one day of history error = nil, want too short")
+	}
+
+	// Add three earlier weeks of prices, cheaper every Wednesday.
+	store, err := history.Open(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().AddDate(0, 0, -21)
+	for i := range 21 {
+		day := start.AddDate(0, 0, i)
+		offer := domain.Offer{Price: 199, ProductID: "B0D1XD1ZV3", Retailer: domain.Amazon}
+		if day.Weekday() == time.Wednesday {
+			offer.Price = 179
+		}
+		if err := store.Record([]domain.Offer{offer}, day); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := run("forecast", "1", "--days", "7")
+	for _, want := range []string{"from 22 days of history", "Expected low over the next 7 days", "Prices tend to be lowest on Wednesdays."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("forecast output missing %q:\n%s", want, out)
+		}
+	}
+
+	run("watch", "remove", "1")
+	if err := runner.Run(context.Background(), []string{"forecast", "1", "--history", historyFile}, io.Discard); err == nil {
+		t.Error("forecast of a removed watch error = nil, want error")
+	}
+}
+
+// TestRunnerExport verifies export reports recorded prices in each format.
+func TestRunnerExport(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := history.Open(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const title = "Apple AirPods Pro 2 Wireless Earbuds"
+	for day, price := range []float64{199, 179, 189} {
+		offers := []domain.Offer{
+			{Title: title, Price: price, ProductID: "B0D1XD1ZV3", Retailer: domain.Amazon},
+			{Title: title, Price: price - 5, ProductID: "5689919121", Retailer: domain.Walmart},
+		}
+		if err := store.Record(offers, time.Date(2026, 10, 1+day, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), append([]string{"export", "--history", historyFile}, args...), &buf); err != nil {
+			t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+		}
+		return buf.String()
+	}
+
+	csv := strings.Split(strings.TrimSpace(run("--from", "2026-10-02", "--to", "2026-10-03")), "\n")
+	if len(csv) != 3 || !strings.Contains(csv[1], "amazon:B0D1XD1ZV3") || !strings.Contains(csv[1], ",179.00,") || !strings.HasSuffix(csv[1], ",2") {
+		t.Errorf("csv export = %q", csv)
+	}
+	if out := run("--format", "json", "--to", "2026-10-01"); !strings.Contains(out, `"to": "2026-10-01"`) || strings.Contains(out, "179") {
+		t.Errorf("json export = %s", out)
+	}
+	htmlFile := filepath.Join(t.TempDir(), "report.html")
+	run("--format", "html", "--from", "2026-10-01", "--output", htmlFile)
+	html, err := os.ReadFile(htmlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "<polyline") || !strings.Contains(string(html), title) {
+		t.Errorf("html export missing chart or title:\n%s", html)
+	}
+
+	for _, args := range [][]string{{"--format", "xml"}, {"--from", "10/01/2026"}, {"--from", "2026-10-03", "--to", "2026-10-01"}, {"--no-history"}} {
+		if err := runner.Run(context.Background(), append([]string{"export", "--history", historyFile}, args...), io.Discard); err == nil {
+			t.Errorf("export %q error = nil, want error", args)
+		}
+	}
+}
+
+// TestRunnerProductInvalidRef verifies product rejects unrecognized references.
+func TestRunnerProductInvalidRef(t *testing.T) {
+	var buf strings.Builder
+	err := runner.Run(context.Background(), []string{"product", "https://example.com/widget"}, &buf)
+	if !errors.Is(err, domain.ErrInvalidRef) {
+		t.Fatalf("expected ErrInvalidRef, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "unrecognized product reference") {
+		t.Errorf("error not reported to the user: %q", buf.String())
+	}
+}
+
+// TestRunnerStream verifies --stream prints every retailer's offers.
+func TestRunnerStream(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--stream", "test query"}, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected header and 6 rows, got:\n%s", buf.String())
+	}
+}
+
+// TestRunnerServe verifies the server starts, answers searches and stops when
+// the context is cancelled.
+func TestRunnerServe(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runner.Run(ctx, []string{"serve", "--addr", "127.0.0.1:18089"}, io.Discard, mockSearchers)
+	}()
+
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get("http://127.0.0.1:18089/v1/search?q=test+product"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		cancel()
+		t.Fatalf("server did not start: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("search status = %d, want 200", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("serve returned %v after cancel", err)
+	}
+}
+
+// TestRunnerMetricsFile verifies --metrics-file dumps the run's metrics.
+func TestRunnerMetricsFile(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:  &mockSearcher{retailer: domain.Amazon},
+		domain.Walmart: &mockSearcher{retailer: domain.Walmart},
+	}
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--metrics-file", path, "test query"}, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("metrics file not written: %v", err)
+	}
+	if !strings.Contains(string(data), `savvyshopper_retailer_requests_total{outcome="ok",retailer="Amazon"}`) {
+		t.Errorf("metrics file missing retailer requests:\n%s", data)
+	}
+}
+
+// TestRunnerLogFlags verifies invalid logging flags are rejected.
+func TestRunnerLogFlags(t *testing.T) {
+	os.Setenv("ZINC_API_KEY", "test-key")
+	defer os.Unsetenv("ZINC_API_KEY")
+
+	mockSearchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon: &mockSearcher{retailer: domain.Amazon},
+	}
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--log-level", "error", "--log-format", "json", "test query"}, &buf, mockSearchers); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, args := range [][]string{{"--log-format", "xml", "q"}, {"--log-level", "loud", "q"}, {"serve", "--log-format", "xml"}} {
+		if err := runner.Run(context.Background(), args, &buf, mockSearchers); err == nil {
+			t.Errorf("Run(%v) should fail", args)
+		}
+	}
+}
+
+// TestRunnerFakeZinc runs the real searchers against the fakezinc command,
+// which requires the API key.
+func TestRunnerFakeZinc(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("ZINC_BASE_URL", "http://127.0.0.1:18090")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runner.Run(ctx, []string{"fakezinc", "--addr", "127.0.0.1:18090", "--api-key", "test-key"}, io.Discard)
+	}()
+	defer func() {
+		cancel()
+		if err := <-errCh; err != nil {
+			t.Errorf("fakezinc returned %v after cancel", err)
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if resp, err := http.Get("http://127.0.0.1:18090/search?query=x&retailer=amazon"); err == nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--min-relevance", "0.5", "airpods pro"}, &buf); err != nil {
+		t.Fatalf("search failed: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Restored Apple AirPods Pro") || strings.Contains(buf.String(), "Silicone Case") {
+		t.Errorf("unexpected search output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := runner.Run(context.Background(), []string{"--marketplace", "amazon.co.uk,amazon.de", "airpods pro"}, &buf); err != nil {
+		t.Fatalf("marketplace search failed: %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"£199.00", "229,00 €", "Amazon UK", "Amazon DE"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("marketplace search output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := runner.Run(context.Background(), []string{"offers", "B0D1XD1ZV3"}, &buf); err != nil {
+		t.Fatalf("offers failed: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Gadget Outlet") {
+		t.Errorf("expected marketplace seller in offers output:\n%s", buf.String())
+	}
+
+	t.Setenv("ZINC_API_KEY", "wrong-key")
+	buf.Reset()
+	if err := runner.Run(context.Background(), []string{"airpods pro"}, &buf); !errors.Is(err, domain.ErrAuth) {
+		t.Errorf("search with the wrong key error = %v, want ErrAuth\n%s", err, buf.String())
+	}
+}
+
+func TestRunnerZincSettingsValidated(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("SAVVY_CONFIG", "")
+	t.Setenv("ZINC_BASE_URL", "")
+	searchers := map[domain.Retailer]price.Searcher{}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--zinc-url", "api.zinc.io", "airpods"}, "invalid Zinc base URL"},
+		{[]string{"--zinc-path", "target=/search/target", "airpods"}, "unknown marketplace"},
+		{[]string{"--zinc-path", "amazon", "airpods"}, "invalid --zinc-path"},
+		{[]string{"--proxy", "corp-proxy:3128", "airpods"}, "invalid proxy"},
+		{[]string{"--config", filepath.Join(t.TempDir(), "missing.json"), "airpods"}, "failed to read config"},
+	}
+	for _, tt := range tests {
+		var buf strings.Builder
+		if err := runner.Run(context.Background(), tt.args, &buf, searchers); err == nil || !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("Run(%q) = %v, output %q; want error mentioning %q", tt.args, err, buf.String(), tt.want)
+		}
+	}
+}
+
+// TestRunnerOpenCopy verifies --open hands the chosen offer's URL to the
+// configured opener and --copy sends it to the clipboard through the
+// terminal.
+func TestRunnerOpenCopy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the opener is a shell script")
+	}
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{retailer: domain.Amazon}}
+	dir := t.TempDir()
+	opened := filepath.Join(dir, "opened")
+	opener := filepath.Join(dir, "opener.sh")
+	if err := os.WriteFile(opener, []byte("#!/bin/sh\nprintf %s \"$1\" > "+opened+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TMUX", "")
+
+	var buf strings.Builder
+	args := []string{"--opener", opener, "--open", "2", "--copy", "1", "test query"}
+	stderr := captureStderr(t)
+	if err := runner.Run(context.Background(), args, &buf, searchers); err != nil {
+		t.Fatalf("Run(%q) failed: %v", args, err)
+	}
+	if got, _ := os.ReadFile(opened); string(got) != "https://example.com/2" {
+		t.Errorf("opener got %q, want the second offer's URL", got)
+	}
+	status := stderr()
+	if !strings.Contains(status, "Opened https://example.com/2\n") || !strings.Contains(status, "Copied https://example.com/1 to the clipboard\n") {
+		t.Errorf("stderr does not report the opened and copied URLs:\n%q", status)
+	}
+	if out := buf.String(); strings.Contains(out, "Opened") || strings.Contains(out, "Copied") || strings.Contains(out, "\033]52") {
+		t.Errorf("output mixes in what was opened and copied:\n%q", out)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--opener", opener, "--open", "9", "test query"}, "only 3 offers listed"},
+		{[]string{"--opener", "false", "--open", "1", "test query"}, "failed to open https://example.com/1"},
+		{[]string{"--copy", "-1", "test query"}, ""},
+		{[]string{"--stream", "--open", "1", "test query"}, ""},
+		{[]string{"compare", "--copy", "1", "test query"}, ""},
+	} {
+		buf.Reset()
+		if err := runner.Run(context.Background(), tt.args, &buf, searchers); err == nil || !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("Run(%q) = %v, output %q; want error mentioning %q", tt.args, err, buf.String(), tt.want)
+		}
+	}
+}
+
+// TestRunnerCopyKeepsOutput verifies --open and --copy leave Markdown, HTML
+// and template output as it would be without them.
+func TestRunnerCopyKeepsOutput(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("TMUX", "")
+	searchers := map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{retailer: domain.Amazon}}
+	for _, format := range [][]string{
+		{"--format", "md"},
+		{"--format", "html"},
+		{"--template", "{{range .Offers}}{{.URL}}\n{{end}}"},
+	} {
+		args := append([]string{"--no-history"}, format...)
+		var plain, shared strings.Builder
+		if err := runner.Run(context.Background(), append(args, "test query"), &plain, searchers); err != nil {
+			t.Fatalf("Run(%q) failed: %v", args, err)
+		}
+		stderr := captureStderr(t)
+		if err := runner.Run(context.Background(), append(args, "--opener", "true", "--open", "1", "--copy", "2", "test query"), &shared, searchers); err != nil {
+			t.Fatalf("Run(%q, --open, --copy) failed: %v", args, err)
+		}
+		stderr()
+		if shared.String() != plain.String() {
+			t.Errorf("%q output changed by --open and --copy:\n%q\nwant\n%q", format, shared.String(), plain.String())
+		}
+	}
+}
+
+// captureStderr redirects os.Stderr until the returned function is called,
+// which returns what was written.
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	restored := false
+	restore := func() string {
+		if restored {
+			return ""
+		}
+		restored = true
+		os.Stderr = orig
+		w.Close()
+		return <-done
+	}
+	t.Cleanup(func() { restore() })
+	return restore
+}
+
+// TestRunnerAffiliateTags verifies offers from Zinc get canonical URLs and
+// the configured marketplace's affiliate tag.
+func TestRunnerAffiliateTags(t *testing.T) {
+	zinc := httptest.NewServer(fakezinc.New(fakezinc.DefaultCatalog()))
+	defer zinc.Close()
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("ZINC_BASE_URL", zinc.URL)
+
+	var buf strings.Builder
+	args := []string{"--no-history", "--affiliate-tag", "amazon.com=team-20", "--template", "{{range .Offers}}{{.Retailer}} {{.URL}}\n{{end}}", "airpods"}
+	if err := runner.Run(context.Background(), args, &buf); err != nil {
+		t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+	}
+	var amazon, walmart int
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		retailer, url, _ := strings.Cut(line, " ")
+		switch retailer {
+		case "Amazon":
+			amazon++
+			if !strings.HasPrefix(url, "https://www.amazon.com/dp/") || !strings.HasSuffix(url, "?tag=team-20") {
+				t.Errorf("Amazon URL = %q, want canonical and tagged", url)
+			}
+		case "Walmart":
+			walmart++
+			if !strings.HasPrefix(url, "https://www.walmart.com/ip/") || strings.Contains(url, "?") {
+				t.Errorf("Walmart URL = %q, want canonical and untagged", url)
+			}
+		}
+	}
+	if amazon == 0 || walmart == 0 {
+		t.Errorf("want offers from both retailers, got:\n%s", buf.String())
+	}
+
+	// The copied link keeps the tag.
+	buf.Reset()
+	args = []string{"--no-history", "--marketplace", "amazon.com", "--affiliate-tag", "amazon.com=team-20", "--copy", "1", "airpods"}
+	t.Setenv("TMUX", "")
+	stderr := captureStderr(t)
+	if err := runner.Run(context.Background(), args, &buf); err != nil {
+		t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+	}
+	if out := stderr(); !regexp.MustCompile(`Copied https://www\.amazon\.com/dp/\w+\?tag=team-20 to the clipboard`).MatchString(out) {
+		t.Errorf("output does not copy the canonical, tagged URL:\n%s", out)
+	}
+
+	// So do product lookups.
+	buf.Reset()
+	args = []string{"product", "--affiliate-tag", "amazon.com=team-20", "B0D1XD1ZV3"}
+	if err := runner.Run(context.Background(), args, &buf); err != nil {
+		t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "https://www.amazon.com/dp/B0D1XD1ZV3?tag=team-20") {
+		t.Errorf("product output does not show the tagged URL:\n%s", buf.String())
+	}
+
+	if err := runner.Run(context.Background(), []string{"--affiliate-tag", "target.com=x", "airpods"}, io.Discard); err == nil {
+		t.Error("Run(--affiliate-tag target.com=x) error = nil, want unknown marketplace")
+	}
+}
+
+// TestRunnerProductCross verifies --cross skips every marketplace of the
+// listing's retailer, not just the one it was found on.
+func TestRunnerProductCross(t *testing.T) {
+	zinc := httptest.NewServer(fakezinc.New(fakezinc.DefaultCatalog()))
+	defer zinc.Close()
+	t.Setenv("ZINC_API_KEY", "test-key")
+	t.Setenv("ZINC_BASE_URL", zinc.URL)
+
+	const title = "Apple AirPods Pro 2 Wireless Earbuds, Active Noise Cancellation"
+	searchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:   &titleSearcher{retailer: domain.Amazon, title: title},
+		domain.AmazonUK: &titleSearcher{retailer: domain.AmazonUK, title: title},
+		domain.Walmart:  &titleSearcher{retailer: domain.Walmart, title: title},
+	}
+	var buf strings.Builder
+	args := []string{"product", "--cross", "B0D1XD1ZV3"}
+	if err := runner.Run(context.Background(), args, &buf, searchers); err != nil {
+		t.Fatalf("Run(%q) failed: %v\n%s", args, err, buf.String())
+	}
+	_, others, ok := strings.Cut(buf.String(), "Other retailers:")
+	if !ok || strings.Contains(others, "Amazon") || !strings.Contains(others, "Walmart") {
+		t.Errorf("other retailers = %q, want Walmart only", others)
+	}
+}
+
+// titleSearcher returns one listing titled title on retailer.
+type titleSearcher struct {
+	retailer domain.Retailer
+	title    string
+}
+
+func (s *titleSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	return []domain.Offer{{Title: s.title, Price: 199, URL: "https://example.com/1", Retailer: s.retailer}}, nil
+}
+
+// TestRunnerMixedCurrencies verifies results from marketplaces in different
+// currencies are sorted within each currency and not limited by one price.
+func TestRunnerMixedCurrencies(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "test-key")
+	searchers := map[domain.Retailer]price.Searcher{
+		domain.Amazon:   &mockSearcher{retailer: domain.Amazon},
+		domain.AmazonJP: &mockSearcher{retailer: domain.AmazonJP},
+	}
+	var buf strings.Builder
+	if err := runner.Run(context.Background(), []string{"--no-history", "--template", "{{range .Offers}}{{.Retailer}};{{end}}", "test query"}, &buf, searchers); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if want := "Amazon;Amazon;Amazon;Amazon JP;Amazon JP;Amazon JP;"; buf.String() != want {
+		t.Errorf("order = %q, want dollars before yen", buf.String())
+	}
+
+	buf.Reset()
+	if err := runner.Run(context.Background(), []string{"--max-price", "30", "test query"}, &buf, searchers); err == nil || !strings.Contains(buf.String(), "single currency") {
+		t.Errorf("Run(--max-price) = %v, output %q; want price limits rejected", err, buf.String())
+	}
+}
@@ -1,12 +1,21 @@
 package config
 
 import (
+	_ "embed"
 	"encoding/json"
 	"os"
 
 	"savvyshopper/domain"
 )
 
+// DefaultZincBaseURL is the root of the Zinc API.
+const DefaultZincBaseURL = "https://api.zinc.io/v1"
+
+// mockData is compiled in so MockData works from any working directory.
+//
+//go:embed mock_data.json
+var mockData []byte
+
 // APIKey returns the Zinc API key from the environment, or ErrAuth if missing.
 // The key is used for HTTP Basic Auth with Zinc API.
 func APIKey() (string, error) {
@@ -14,25 +23,15 @@ func APIKey() (string, error) {
 	if key == "" {
 		return "", domain.ErrAuth
 	}
-	if key == "mock-api-key-for-testing" {
-		// Return mock data for testing
-		return "mock-data", nil
-	}
 	return key, nil
 }
 
 // MockData returns a slice of mock offers for testing.
 func MockData() ([]domain.Offer, error) {
-	// Read mock data from a JSON file
-	data, err := os.ReadFile("mock_data.json")
-	if err != nil {
-		return nil, err
-	}
-
 	var mockOffers struct {
 		Offers []domain.Offer `json:"offers"`
 	}
-	if err := json.Unmarshal(data, &mockOffers); err != nil {
+	if err := json.Unmarshal(mockData, &mockOffers); err != nil {
 		return nil, err
 	}
 
@@ -31,9 +31,9 @@ func TestAPIKey(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			name:    "mock key set",
+			name:    "former mock key is not special",
 			envKey:  "mock-api-key-for-testing",
-			want:    "mock-data",
+			want:    "mock-api-key-for-testing",
 			wantErr: nil,
 		},
 	}
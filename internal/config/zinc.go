@@ -0,0 +1,312 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+// ConfigEnv names the environment variable holding the config file path.
+const ConfigEnv = "SAVVY_CONFIG"
+
+// HistoryEnv names the environment variable holding the price history path.
+const HistoryEnv = "SAVVY_HISTORY"
+
+// OpenerEnv names the environment variable holding the command offers are
+// opened with.
+const OpenerEnv = "SAVVY_OPENER"
+
+// Config is the contents of the config file.
+type Config struct {
+	Zinc    Zinc    `json:"zinc"`
+	Profile Profile `json:"profile"`
+	// History is the file prices are recorded to; empty means the default.
+	History string `json:"history,omitempty"`
+	// Opener is the command --open runs with an offer's URL, such as
+	// "firefox --new-tab"; empty means the system's default browser.
+	Opener string `json:"opener,omitempty"`
+}
+
+// Profile declares what the shopper qualifies for, so offers show the price
+// they would actually pay.
+type Profile struct {
+	// Memberships lists paid programs such as prime and walmart_plus.
+	Memberships []string `json:"memberships,omitempty"`
+	// SubscribeSave means recurring-order discounts count.
+	SubscribeSave bool `json:"subscribe_save,omitempty"`
+}
+
+// Zinc holds the settings for reaching the Zinc API.
+type Zinc struct {
+	// BaseURL is the root of the Zinc API.
+	BaseURL string `json:"base_url,omitempty"`
+	// Paths maps a marketplace's Zinc code, such as amazon_uk, to its search
+	// endpoint, relative to BaseURL.
+	Paths map[string]string `json:"paths,omitempty"`
+	// Marketplaces lists the sites to search, by domain such as amazon.co.uk.
+	// Empty means domain.DefaultMarketplaces.
+	Marketplaces []string `json:"marketplaces,omitempty"`
+	// Proxy is the URL of an HTTP proxy for Zinc requests. Without it the
+	// standard HTTPS_PROXY and NO_PROXY variables apply.
+	Proxy string `json:"proxy,omitempty"`
+	// CAFile is a PEM file of extra certificate authorities to trust, such
+	// as a corporate TLS-inspecting proxy's.
+	CAFile string `json:"ca_file,omitempty"`
+	// AffiliateTags maps a marketplace, by domain such as amazon.co.uk, to
+	// the referral tag added to its offers' URLs.
+	AffiliateTags map[string]string `json:"affiliate_tags,omitempty"`
+}
+
+// DefaultZinc returns the settings for the public Zinc API.
+func DefaultZinc() Zinc {
+	z := Zinc{BaseURL: DefaultZincBaseURL, Paths: make(map[string]string)}
+	for _, m := range domain.Marketplaces() {
+		z.Paths[m.Zinc] = "/search/" + m.Zinc
+	}
+	return z
+}
+
+// Load returns the default settings overlaid first with the config file and
+// then with the environment. The file is path, or SAVVY_CONFIG if path is
+// empty, or savvyshopper/config.json in the user config directory if that
+// exists. The result is not validated, since flags may still override it.
+func Load(path string) (Config, error) {
+	cfg := Config{Zinc: DefaultZinc()}
+	explicit := true
+	if path == "" {
+		path = os.Getenv(ConfigEnv)
+	}
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg.WithEnv(), nil
+		}
+		path, explicit = filepath.Join(dir, "savvyshopper", "config.json"), false
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		return cfg.WithEnv(), nil
+	case err != nil:
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+	var file Config
+	if err := json.Unmarshal(data, &file); err != nil {
+		return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	cfg.Zinc = cfg.Zinc.Merge(file.Zinc)
+	cfg.Profile = file.Profile
+	cfg.History = file.History
+	cfg.Opener = file.Opener
+	return cfg.WithEnv(), nil
+}
+
+// WithEnv returns c overlaid with ZINC_BASE_URL, ZINC_<MARKETPLACE>_PATH (for
+// example ZINC_AMAZON_UK_PATH), ZINC_MARKETPLACES, ZINC_PROXY, ZINC_CA_FILE,
+// SAVVY_HISTORY and SAVVY_OPENER.
+func (c Config) WithEnv() Config {
+	if h := os.Getenv(HistoryEnv); h != "" {
+		c.History = h
+	}
+	if o := os.Getenv(OpenerEnv); o != "" {
+		c.Opener = o
+	}
+	env := Zinc{
+		BaseURL: os.Getenv("ZINC_BASE_URL"),
+		Proxy:   os.Getenv("ZINC_PROXY"),
+		CAFile:  os.Getenv("ZINC_CA_FILE"),
+		Paths:   make(map[string]string),
+	}
+	for _, m := range strings.Split(os.Getenv("ZINC_MARKETPLACES"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			env.Marketplaces = append(env.Marketplaces, m)
+		}
+	}
+	for retailer := range c.Zinc.Paths {
+		if p := os.Getenv("ZINC_" + strings.ToUpper(retailer) + "_PATH"); p != "" {
+			env.Paths[retailer] = p
+		}
+	}
+	c.Zinc = c.Zinc.Merge(env)
+	return c
+}
+
+// Merge returns z with every field set in over replacing its own.
+func (z Zinc) Merge(over Zinc) Zinc {
+	if over.BaseURL != "" {
+		z.BaseURL = over.BaseURL
+	}
+	if over.Proxy != "" {
+		z.Proxy = over.Proxy
+	}
+	if over.CAFile != "" {
+		z.CAFile = over.CAFile
+	}
+	if len(over.Marketplaces) > 0 {
+		z.Marketplaces = over.Marketplaces
+	}
+	paths := make(map[string]string, len(z.Paths))
+	for r, p := range z.Paths {
+		paths[r] = p
+	}
+	for r, p := range over.Paths {
+		paths[strings.ToLower(r)] = p
+	}
+	z.Paths = paths
+	if len(z.AffiliateTags)+len(over.AffiliateTags) > 0 {
+		tags := make(map[string]string, len(z.AffiliateTags))
+		for _, from := range []map[string]string{z.AffiliateTags, over.AffiliateTags} {
+			for m, tag := range from {
+				tags[marketplaceKey(m)] = tag
+			}
+		}
+		z.AffiliateTags = tags
+	}
+	z.BaseURL = strings.TrimRight(z.BaseURL, "/")
+	return z
+}
+
+// marketplaceKey returns the domain of the marketplace m names in any of the
+// ways domain.ParseMarketplace accepts, or m in lower case if it names none.
+func marketplaceKey(m string) string {
+	if r, err := domain.ParseMarketplace(m); err == nil {
+		return r.Marketplace().Domain
+	}
+	return strings.ToLower(m)
+}
+
+// Validate reports the first invalid setting: a base URL or proxy that is not
+// an absolute http or https URL, an unknown marketplace, a path for a
+// marketplace savvyshopper does not search or one not starting with '/', a
+// CA file that holds no certificates, or an affiliate tag for an unknown
+// marketplace.
+func (z Zinc) Validate() error {
+	if err := checkURL("Zinc base URL", z.BaseURL); err != nil {
+		return err
+	}
+	if _, err := z.Retailers(); err != nil {
+		return err
+	}
+	known := DefaultZinc().Paths
+	for retailer, p := range z.Paths {
+		if _, ok := known[retailer]; !ok {
+			return fmt.Errorf("invalid Zinc path for %q: unknown marketplace", retailer)
+		}
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("invalid Zinc path %q for %s: must start with /", p, retailer)
+		}
+	}
+	if z.Proxy != "" {
+		if err := checkURL("proxy", z.Proxy); err != nil {
+			return err
+		}
+	}
+	if z.CAFile != "" {
+		if _, err := z.certPool(); err != nil {
+			return err
+		}
+	}
+	for m := range z.AffiliateTags {
+		if _, err := domain.ParseMarketplace(m); err != nil {
+			return fmt.Errorf("invalid affiliate tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// Tags returns the affiliate tags by retailer. Unknown marketplaces, which
+// Validate reports, are skipped.
+func (z Zinc) Tags() map[domain.Retailer]string {
+	tags := make(map[domain.Retailer]string, len(z.AffiliateTags))
+	for m, tag := range z.AffiliateTags {
+		if r, err := domain.ParseMarketplace(m); err == nil && tag != "" {
+			tags[r] = tag
+		}
+	}
+	return tags
+}
+
+func checkURL(what, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q: want an http or https URL", what, raw)
+	}
+	return nil
+}
+
+// Retailers returns the marketplaces to search.
+func (z Zinc) Retailers() ([]domain.Retailer, error) {
+	if len(z.Marketplaces) == 0 {
+		return domain.DefaultMarketplaces, nil
+	}
+	retailers := make([]domain.Retailer, 0, len(z.Marketplaces))
+	for _, m := range z.Marketplaces {
+		r, err := domain.ParseMarketplace(m)
+		if err != nil {
+			return nil, err
+		}
+		retailers = append(retailers, r)
+	}
+	return retailers, nil
+}
+
+// SearchURL returns the search endpoint for retailer, or "" if it has no
+// path configured.
+func (z Zinc) SearchURL(retailer domain.Retailer) string {
+	p, ok := z.Paths[retailer.Marketplace().Zinc]
+	if !ok {
+		return ""
+	}
+	return z.BaseURL + p
+}
+
+// RequestTimeout bounds each HTTP attempt made with a Zinc client.
+const RequestTimeout = 10 * time.Second
+
+// HTTPClient returns a client that goes through the configured proxy and
+// trusts the configured CA file in addition to the system roots.
+func (z Zinc) HTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if z.Proxy != "" {
+		proxy, err := url.Parse(z.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", z.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if z.CAFile != "" {
+		pool, err := z.certPool()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return &http.Client{Transport: transport, Timeout: RequestTimeout}, nil
+}
+
+// certPool returns the system roots plus the certificates in CAFile.
+func (z Zinc) certPool() (*x509.CertPool, error) {
+	pem, err := os.ReadFile(z.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("invalid CA file %s: no PEM certificates found", z.CAFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func clearZincEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{ConfigEnv, HistoryEnv, OpenerEnv, "ZINC_BASE_URL", "ZINC_PROXY", "ZINC_CA_FILE", "ZINC_AMAZON_PATH", "ZINC_WALMART_PATH"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearZincEnv(t)
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Zinc.SearchURL(domain.Amazon); got != "https://api.zinc.io/v1/search/amazon" {
+		t.Errorf("SearchURL(Amazon) = %q", got)
+	}
+	if err := cfg.Zinc.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	clearZincEnv(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"zinc": {"base_url": "https://zinc.staging.example/v1/", "paths": {"Walmart": "/v2/search/walmart"}, "proxy": "http://proxy.corp:3128"}, "history": "/var/lib/savvy/history.jsonl", "opener": "firefox --new-tab"}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+	t.Setenv("ZINC_AMAZON_PATH", "/amazon")
+	t.Setenv("ZINC_BASE_URL", "http://localhost:8090")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	z := cfg.Zinc
+	if got := z.SearchURL(domain.Amazon); got != "http://localhost:8090/amazon" {
+		t.Errorf("SearchURL(Amazon) = %q, want env to win", got)
+	}
+	if got := z.SearchURL(domain.Walmart); got != "http://localhost:8090/v2/search/walmart" {
+		t.Errorf("SearchURL(Walmart) = %q, want file path", got)
+	}
+	if z.Proxy != "http://proxy.corp:3128" {
+		t.Errorf("Proxy = %q", z.Proxy)
+	}
+	if cfg.History != "/var/lib/savvy/history.jsonl" {
+		t.Errorf("History = %q, want file value", cfg.History)
+	}
+
+	t.Setenv(HistoryEnv, "/tmp/history.jsonl")
+	if cfg, _ := Load(""); cfg.History != "/tmp/history.jsonl" {
+		t.Errorf("History = %q, want env to win", cfg.History)
+	}
+	if cfg.Opener != "firefox --new-tab" {
+		t.Errorf("Opener = %q, want file value", cfg.Opener)
+	}
+	t.Setenv(OpenerEnv, "xdg-open")
+	if cfg, _ := Load(""); cfg.Opener != "xdg-open" {
+		t.Errorf("Opener = %q, want env to win", cfg.Opener)
+	}
+}
+
+func TestLoad_MissingExplicitFile(t *testing.T) {
+	clearZincEnv(t)
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() error = nil, want error for a missing --config file")
+	}
+}
+
+func TestZincValidate(t *testing.T) {
+	badCA := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(badCA, []byte("not a certificate"), 0o644)
+
+	tests := []struct {
+		name string
+		over Zinc
+		want string
+	}{
+		{"relative base URL", Zinc{BaseURL: "api.zinc.io/v1"}, "invalid Zinc base URL"},
+		{"ftp base URL", Zinc{BaseURL: "ftp://api.zinc.io"}, "invalid Zinc base URL"},
+		{"unknown retailer", Zinc{Paths: map[string]string{"target": "/search/target"}}, "unknown marketplace"},
+		{"path without slash", Zinc{Paths: map[string]string{"amazon": "search/amazon"}}, "must start with /"},
+		{"unknown marketplace", Zinc{Marketplaces: []string{"amazon.fr"}}, "invalid marketplace"},
+		{"bad proxy", Zinc{Proxy: "proxy.corp:3128"}, "invalid proxy"},
+		{"missing CA file", Zinc{CAFile: filepath.Join(t.TempDir(), "none.pem")}, "failed to read CA file"},
+		{"CA file without certificates", Zinc{CAFile: badCA}, "no PEM certificates"},
+		{"affiliate tag for unknown marketplace", Zinc{AffiliateTags: map[string]string{"target.com": "team"}}, "invalid affiliate tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultZinc().Merge(tt.over).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestZincTags(t *testing.T) {
+	file := Zinc{AffiliateTags: map[string]string{"amazon.com": "old-20", "Amazon UK": "team-21"}}
+	z := DefaultZinc().Merge(file).Merge(Zinc{AffiliateTags: map[string]string{"amazon": "team-20", "walmart.com": "1234"}})
+	if err := z.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	want := map[domain.Retailer]string{domain.Amazon: "team-20", domain.AmazonUK: "team-21", domain.Walmart: "1234"}
+	got := z.Tags()
+	if len(got) != len(want) {
+		t.Fatalf("Tags() = %v, want %v", got, want)
+	}
+	for r, tag := range want {
+		if got[r] != tag {
+			t.Errorf("Tags()[%s] = %q, want %q", r, got[r], tag)
+		}
+	}
+}
+
+func TestZincHTTPClient_Proxy(t *testing.T) {
+	z := DefaultZinc().Merge(Zinc{Proxy: "http://proxy.corp:3128"})
+	client, err := z.HTTPClient()
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	req, _ := http.NewRequest("GET", "https://api.zinc.io/v1/search/amazon", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.corp:3128" {
+		t.Errorf("Proxy(req) = %v, %v; want proxy.corp:3128", proxy, err)
+	}
+}
@@ -0,0 +1,178 @@
+// Package deal rates how good a buy each offer is, from its discount off list
+// price, how its price compares with the recent lows in the price history,
+// and how far the retailer and seller can be trusted.
+package deal
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+)
+
+// Score weights; they add up to 100.
+const (
+	discountWeight    = 30
+	historyWeight     = 40
+	reliabilityWeight = 15
+	sellerWeight      = 15
+)
+
+const (
+	// fullDiscount is the discount off list price that earns the whole
+	// discount weight.
+	fullDiscount = 0.4
+	// historyRange is how far above a recent low a price earns nothing for
+	// its history.
+	historyRange = 0.2
+	// nearLow is how far above a recent low still counts as at the low.
+	nearLow = 0.02
+	// buyDiscount is the discount off list price worth buying at without
+	// any price history.
+	buyDiscount = 0.2
+	// minSellerRating is the positive feedback percentage below which a
+	// seller is not worth the risk.
+	minSellerRating = 90
+)
+
+// reliability rates each retailer, by brand, from 0 to 1 for getting orders
+// delivered as described. Retailers not listed get defaultReliability.
+var reliability = map[domain.Retailer]float64{
+	domain.Amazon:  1,
+	domain.Walmart: 0.95,
+}
+
+const defaultReliability = 0.8
+
+// Scorer rates offers against their price history.
+type Scorer struct {
+	// History holds the prices offers were seen at before; nil rates offers
+	// on their discount and seller alone.
+	History *history.Store
+	// Now is when the offers were seen; zero means time.Now.
+	Now time.Time
+}
+
+// Score sets Deal on each offer and returns the slice.
+func (s Scorer) Score(offers []domain.Offer) []domain.Offer {
+	now := s.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	for i := range offers {
+		o := &offers[i]
+		lows := s.lows(*o, now)
+		score := discountWeight*min(o.Discount()/fullDiscount, 1) +
+			historyWeight*lows.score(o.Price) +
+			reliabilityWeight*retailerScore(*o) +
+			sellerWeight*sellerScore(*o)
+		o.Deal = domain.Deal{Score: int(math.Round(score))}
+		o.Deal.Advice, o.Deal.Reason = advise(*o, lows)
+	}
+	return offers
+}
+
+// lows are an offer's lowest recorded prices over the last 30 and 90 days;
+// zero when there is no history. An offer not seen in the last 30 days takes
+// its 90-day low for both.
+type lows struct {
+	month, quarter float64
+}
+
+func (s Scorer) lows(o domain.Offer, now time.Time) lows {
+	if s.History == nil {
+		return lows{}
+	}
+	key := history.Key(o)
+	var l lows
+	if p, ok := s.History.Low(key, now.AddDate(0, 0, -30)); ok {
+		l.month = p.Price
+	}
+	if p, ok := s.History.Low(key, now.AddDate(0, 0, -90)); ok {
+		l.quarter = p.Price
+	}
+	if l.month == 0 {
+		l.month = l.quarter
+	}
+	return l
+}
+
+// score rates price from 0 to 1 against the lows, scoring 1 at or below a
+// low and nothing historyRange above it. Without history it is neutral.
+func (l lows) score(price float64) float64 {
+	if l.quarter <= 0 {
+		return 0.5
+	}
+	closeness := func(low float64) float64 {
+		if low <= 0 {
+			return 0
+		}
+		return min(max(1-(price/low-1)/historyRange, 0), 1)
+	}
+	return (closeness(l.month) + closeness(l.quarter)) / 2
+}
+
+// retailerScore rates the retailer's reliability, discounted when a
+// marketplace seller fills the order.
+func retailerScore(o domain.Offer) float64 {
+	r, ok := reliability[o.Retailer.Marketplace().Brand]
+	if !ok {
+		r = defaultReliability
+	}
+	switch {
+	case o.Seller == "":
+		return r
+	case o.Fulfillment == domain.FulfilledByRetailer:
+		return r * 0.9
+	default:
+		return r * 0.75
+	}
+}
+
+// sellerScore rates the seller's feedback, scoring 1 for the retailer itself
+// and nothing at 80% positive or below. Unrated sellers are neutral.
+func sellerScore(o domain.Offer) float64 {
+	switch {
+	case o.Seller == "":
+		return 1
+	case o.SellerRating <= 0:
+		return 0.5
+	}
+	return min(max((o.SellerRating-80)/20, 0), 1)
+}
+
+// advise says whether to buy o now, and why in a short phrase.
+func advise(o domain.Offer, l lows) (domain.Advice, string) {
+	if o.Seller != "" && o.SellerRating > 0 && o.SellerRating < minSellerRating {
+		return domain.AdviceWait, fmt.Sprintf("seller rated only %.0f%% positive", o.SellerRating)
+	}
+	if l.quarter > 0 {
+		above := o.Price/l.quarter - 1
+		switch {
+		case above <= 0:
+			return domain.AdviceBuy, "at its 90-day low"
+		case above <= nearLow:
+			return domain.AdviceBuy, fmt.Sprintf("within %s of its 90-day low", percent(above))
+		case o.Price <= l.month*(1+nearLow):
+			return domain.AdviceBuy, fmt.Sprintf("at its 30-day low, %s above the 90-day low", percent(above))
+		default:
+			return domain.AdviceWait, fmt.Sprintf("%s above its 30-day low", percent(o.Price/l.month-1))
+		}
+	}
+	switch d := o.Discount(); {
+	case d >= buyDiscount:
+		return domain.AdviceBuy, fmt.Sprintf("%s off list price; no price history yet", percent(d))
+	case d >= 0.005:
+		return domain.AdviceWait, fmt.Sprintf("only %s off list price and no price history yet", percent(d))
+	default:
+		return domain.AdviceWait, "not discounted and no price history yet"
+	}
+}
+
+// percent formats a positive fraction as a whole percentage, at least 1% so a
+// small difference never shows as none.
+func percent(f float64) string {
+	return fmt.Sprintf("%.0f%%", max(math.Round(f*100), 1))
+}
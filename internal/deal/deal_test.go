@@ -0,0 +1,95 @@
+package deal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+)
+
+func TestScorer_Score(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := func(id string, price float64, daysAgo int) {
+		o := domain.Offer{Price: price, ProductID: id, Retailer: domain.Amazon}
+		if err := store.Record([]domain.Offer{o}, now.AddDate(0, 0, -daysAgo)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seen("LOW", 100, 60)
+	seen("LOW", 120, 5)
+	seen("MONTH", 90, 60)
+	seen("MONTH", 100, 20)
+	seen("HIGH", 100, 10)
+	seen("OLD", 100, 60)
+
+	tests := []struct {
+		name   string
+		offer  domain.Offer
+		score  int
+		advice domain.Advice
+		reason string
+	}{
+		{
+			name:   "at the 90-day low",
+			offer:  domain.Offer{Price: 100, ProductID: "LOW", Retailer: domain.Amazon},
+			score:  70,
+			advice: domain.AdviceBuy,
+			reason: "at its 90-day low",
+		},
+		{
+			name:   "at the 30-day low only",
+			offer:  domain.Offer{Price: 100, ProductID: "MONTH", Retailer: domain.Amazon},
+			score:  59,
+			advice: domain.AdviceBuy,
+			reason: "at its 30-day low, 11% above the 90-day low",
+		},
+		{
+			name:   "above the recent low",
+			offer:  domain.Offer{Price: 115, ProductID: "HIGH", Retailer: domain.Amazon},
+			score:  40,
+			advice: domain.AdviceWait,
+			reason: "15% above its 30-day low",
+		},
+		{
+			name:   "not seen in the last 30 days",
+			offer:  domain.Offer{Price: 115, ProductID: "OLD", Retailer: domain.Amazon},
+			score:  40,
+			advice: domain.AdviceWait,
+			reason: "15% above its 30-day low",
+		},
+		{
+			name:   "discounted without history",
+			offer:  domain.Offer{Price: 75, ListPrice: 100, ProductID: "NEW", Retailer: domain.Amazon},
+			score:  69,
+			advice: domain.AdviceBuy,
+			reason: "25% off list price; no price history yet",
+		},
+		{
+			name:   "poorly rated seller",
+			offer:  domain.Offer{Price: 100, ProductID: "LOW", Retailer: domain.Walmart, Seller: "Gadget Outlet", SellerRating: 85},
+			score:  34,
+			advice: domain.AdviceWait,
+			reason: "seller rated only 85% positive",
+		},
+	}
+	scorer := Scorer{History: store, Now: now}
+	for _, tt := range tests {
+		got := scorer.Score([]domain.Offer{tt.offer})[0].Deal
+		if got.Score != tt.score || got.Advice != tt.advice || got.Reason != tt.reason {
+			t.Errorf("%s: Deal = %+v, want {Score:%d Advice:%s Reason:%s}", tt.name, got, tt.score, tt.advice, tt.reason)
+		}
+	}
+}
+
+func TestScorer_NoHistory(t *testing.T) {
+	got := Scorer{}.Score([]domain.Offer{{Price: 100, Retailer: domain.Amazon}})[0].Deal
+	if got.Advice != domain.AdviceWait || got.Reason != "not discounted and no price history yet" {
+		t.Errorf("Deal = %+v", got)
+	}
+}
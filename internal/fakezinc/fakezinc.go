@@ -0,0 +1,351 @@
+// Package fakezinc serves a Zinc-compatible API from seed data, for local
+// development and end-to-end tests that should not touch the real service.
+// Knobs in Options inject latency, server errors, rate limiting and malformed
+// payloads so the client's failure handling can be exercised on demand.
+package fakezinc
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// seed is the catalog served when no data directory is given.
+//
+//go:embed seed
+var seed embed.FS
+
+// Options tunes how the fake server misbehaves. The zero value answers every
+// request immediately and correctly.
+type Options struct {
+	// Latency delays every response.
+	Latency time.Duration
+	// ErrorRate is the fraction of requests answered with 500.
+	ErrorRate float64
+	// RateLimitRate is the fraction of requests answered with 429.
+	RateLimitRate float64
+	// MalformedRate is the fraction of successful responses whose JSON body
+	// is cut short.
+	MalformedRate float64
+	// Seed makes the failures reproducible. Zero picks a random seed.
+	Seed int64
+	// APIKey, if set, must be sent as the Basic Auth username.
+	APIKey string
+	// PollsUntilReady is how many times an async request is reported as
+	// still processing before its result is returned.
+	PollsUntilReady int
+}
+
+// Catalog is the seed data a Server answers from. A data directory holds
+//
+//	search/<retailer>.json            array of search results
+//	products/<retailer>/<id>.json     product details
+//	offers/<retailer>/<id>.json       {"offers": [...]}
+//
+// in Zinc's own JSON format; retailers are Zinc's lower-case codes, such as
+// amazon or amazon_uk.
+type Catalog struct {
+	search   map[string][]searchEntry
+	products map[string]json.RawMessage
+	offers   map[string]json.RawMessage
+}
+
+type searchEntry struct {
+	title string
+	raw   json.RawMessage
+}
+
+// DefaultCatalog returns the built-in seed catalog.
+func DefaultCatalog() *Catalog {
+	sub, err := fs.Sub(seed, "seed")
+	if err == nil {
+		var c *Catalog
+		if c, err = Load(sub); err == nil {
+			return c
+		}
+	}
+	panic(fmt.Sprintf("fakezinc: invalid built-in seed data: %v", err))
+}
+
+// LoadDir reads a catalog from a data directory.
+func LoadDir(dir string) (*Catalog, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load reads a catalog laid out as described on Catalog.
+func Load(fsys fs.FS) (*Catalog, error) {
+	c := &Catalog{
+		search:   make(map[string][]searchEntry),
+		products: make(map[string]json.RawMessage),
+		offers:   make(map[string]json.RawMessage),
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(strings.TrimSuffix(name, ".json"), "/")
+		switch {
+		case len(parts) == 2 && parts[0] == "search":
+			var results []json.RawMessage
+			if err := json.Unmarshal(data, &results); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			for _, raw := range results {
+				var r struct {
+					Title string `json:"title"`
+				}
+				if err := json.Unmarshal(raw, &r); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				c.search[parts[1]] = append(c.search[parts[1]], searchEntry{title: strings.ToLower(r.Title), raw: raw})
+			}
+		case len(parts) == 3 && (parts[0] == "products" || parts[0] == "offers"):
+			if !json.Valid(data) {
+				return fmt.Errorf("%s: invalid JSON", name)
+			}
+			dst := c.products
+			if parts[0] == "offers" {
+				dst = c.offers
+			}
+			dst[parts[1]+"/"+parts[2]] = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fake Zinc data: %w", err)
+	}
+	return c, nil
+}
+
+// Server is a fake Zinc API.
+type Server struct {
+	catalog *Catalog
+	opts    Options
+	mux     *http.ServeMux
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	nextID  int
+	pending map[string]*asyncRequest
+}
+
+// asyncRequest is a result waiting to be polled for.
+type asyncRequest struct {
+	polls  int
+	status int
+	body   any
+}
+
+// New creates a Server answering from catalog.
+// If optsOpt is provided, it uses those options instead of the defaults.
+func New(catalog *Catalog, optsOpt ...Options) *Server {
+	s := &Server{
+		catalog: catalog,
+		mux:     http.NewServeMux(),
+		pending: make(map[string]*asyncRequest),
+	}
+	if len(optsOpt) > 0 {
+		s.opts = optsOpt[0]
+	}
+	seed := s.opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s.rng = rand.New(rand.NewSource(seed))
+
+	s.mux.HandleFunc("POST /search/{retailer}", s.handleSearch)
+	s.mux.HandleFunc("GET /search", s.handleSearch)
+	s.mux.HandleFunc("GET /products/{id}", s.handleProduct)
+	s.mux.HandleFunc("GET /products/{id}/offers", s.handleOffers)
+	s.mux.HandleFunc("GET /requests/{id}", s.handlePoll)
+	return s
+}
+
+// ServeHTTP implements http.Handler, applying the failure knobs before
+// routing.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if s.opts.APIKey != "" {
+		if user, _, ok := r.BasicAuth(); !ok || user != s.opts.APIKey {
+			writeJSON(w, http.StatusUnauthorized, zincError("invalid_api_key", "The API key is missing or invalid."))
+			return
+		}
+	}
+	switch roll := s.roll(); {
+	case roll < s.opts.RateLimitRate:
+		w.Header().Set("Retry-After", "1")
+		writeJSON(w, http.StatusTooManyRequests, zincError("too_many_requests", "Rate limit exceeded."))
+		return
+	case roll < s.opts.RateLimitRate+s.opts.ErrorRate:
+		writeJSON(w, http.StatusInternalServerError, zincError("internal_error", "An internal error occurred."))
+		return
+	}
+	if s.roll() < s.opts.MalformedRate {
+		w = &truncatingWriter{ResponseWriter: w}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// roll draws a number in [0, 1).
+func (s *Server) roll() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+// handleSearch answers both the POST form the savvyshopper client sends and
+// Zinc's GET /search?query=...&retailer=... form.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	retailer := r.PathValue("retailer")
+	query := r.URL.Query().Get("query")
+	maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+	if r.Method == http.MethodPost {
+		var payload struct {
+			SearchTerm string `json:"search_term"`
+			Retailer   string `json:"retailer"`
+			MaxResults int    `json:"max_results"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, zincError("invalid_request", "Invalid JSON body."))
+			return
+		}
+		query, maxResults = payload.SearchTerm, payload.MaxResults
+		// The path names the marketplace, e.g. amazon_uk, while the payload
+		// only carries the brand.
+		if retailer == "" {
+			retailer = payload.Retailer
+		}
+	} else {
+		retailer = r.URL.Query().Get("retailer")
+	}
+	if query == "" || retailer == "" {
+		writeJSON(w, http.StatusBadRequest, zincError("invalid_request", "query and retailer are required."))
+		return
+	}
+	retailer = strings.ToLower(retailer)
+
+	results := []json.RawMessage{}
+	tokens := strings.Fields(strings.ToLower(query))
+	for _, e := range s.catalog.search[retailer] {
+		if matches(e.title, tokens) {
+			results = append(results, e.raw)
+		}
+	}
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	s.respond(w, r, http.StatusOK, map[string]any{"status": "completed", "retailer": retailer, "results": results})
+}
+
+func (s *Server) handleProduct(w http.ResponseWriter, r *http.Request) {
+	s.lookup(w, r, s.catalog.products)
+}
+
+func (s *Server) handleOffers(w http.ResponseWriter, r *http.Request) {
+	s.lookup(w, r, s.catalog.offers)
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request, from map[string]json.RawMessage) {
+	retailer := strings.ToLower(r.URL.Query().Get("retailer"))
+	if retailer == "" {
+		writeJSON(w, http.StatusBadRequest, zincError("invalid_request", "retailer is required."))
+		return
+	}
+	raw, ok := from[retailer+"/"+r.PathValue("id")]
+	if !ok {
+		s.respond(w, r, http.StatusNotFound, zincError("product_not_found", "No product with that ID."))
+		return
+	}
+	s.respond(w, r, http.StatusOK, raw)
+}
+
+// respond writes body, or with ?async=true parks it to be fetched from
+// /requests/{id}, as Zinc does for slow lookups.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, status int, body any) {
+	if async, _ := strconv.ParseBool(r.URL.Query().Get("async")); !async {
+		writeJSON(w, status, body)
+		return
+	}
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("req_%d", s.nextID)
+	s.pending[id] = &asyncRequest{status: status, body: body}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusAccepted, map[string]string{"request_id": id, "status": "processing"})
+}
+
+// handlePoll reports an async request as processing until it has been polled
+// PollsUntilReady times, then returns its result once.
+func (s *Server) handlePoll(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	req, ok := s.pending[id]
+	ready := ok && req.polls >= s.opts.PollsUntilReady
+	if ok {
+		req.polls++
+	}
+	if ready {
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeJSON(w, http.StatusNotFound, zincError("request_not_found", "No request with that ID."))
+	case !ready:
+		writeJSON(w, http.StatusAccepted, map[string]string{"request_id": id, "status": "processing", "code": "request_processing"})
+	default:
+		writeJSON(w, req.status, req.body)
+	}
+}
+
+// matches reports whether title contains every query token.
+func matches(title string, tokens []string) bool {
+	for _, t := range tokens {
+		if !strings.Contains(title, t) {
+			return false
+		}
+	}
+	return len(tokens) > 0
+}
+
+func zincError(code, message string) map[string]any {
+	return map[string]any{"_type": "error", "code": code, "message": message}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// truncatingWriter drops the second half of the body, leaving invalid JSON.
+type truncatingWriter struct {
+	http.ResponseWriter
+}
+
+func (t *truncatingWriter) Write(p []byte) (int, error) {
+	if _, err := t.ResponseWriter.Write(p[:len(p)/2]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,146 @@
+package fakezinc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/price"
+)
+
+func TestSearchAndProduct(t *testing.T) {
+	srv := httptest.NewServer(New(DefaultCatalog()))
+	defer srv.Close()
+
+	offers, err := price.SearchPrices(context.Background(), "airpods pro", map[domain.Retailer]price.Searcher{
+		domain.Amazon:  price.NewAmazonSearcher(srv.URL + "/search/amazon"),
+		domain.Walmart: price.NewWalmartSearcher(srv.URL + "/search/walmart"),
+	})
+	if err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+	if len(offers) != 5 || offers[0].Price != 8.99 {
+		t.Errorf("unexpected offers %+v", offers)
+	}
+
+	details, err := price.NewProductClient(srv.URL).Lookup(context.Background(), domain.Amazon, "B0D1XD1ZV3")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if details.Brand != "Apple" || len(details.Variants) != 2 || len(details.Offers) != 3 {
+		t.Errorf("unexpected details %+v", details)
+	}
+	if _, err := price.NewProductClient(srv.URL).Details(context.Background(), domain.Walmart, "0000"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown product error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFailureKnobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		status  int
+		wantErr error
+	}{
+		{"rate limited", Options{RateLimitRate: 1}, http.StatusTooManyRequests, domain.ErrNetwork},
+		{"server error", Options{ErrorRate: 1}, http.StatusInternalServerError, domain.ErrNetwork},
+		{"malformed", Options{MalformedRate: 1}, http.StatusOK, domain.ErrNetwork},
+		{"unauthorized", Options{APIKey: "secret"}, http.StatusUnauthorized, domain.ErrAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(New(DefaultCatalog(), tt.opts))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/search?query=airpods&retailer=amazon")
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			_, err = price.NewAmazonSearcher(srv.URL+"/search/amazon").Search(context.Background(), "airpods")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Search() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv := httptest.NewServer(New(DefaultCatalog(), Options{Latency: 50 * time.Millisecond}))
+	defer srv.Close()
+
+	start := time.Now()
+	if _, err := price.NewAmazonSearcher(srv.URL+"/search/amazon").Search(context.Background(), "airpods"); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("response took %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestAsyncPoll(t *testing.T) {
+	srv := httptest.NewServer(New(DefaultCatalog(), Options{PollsUntilReady: 2}))
+	defer srv.Close()
+
+	var accepted struct {
+		RequestID string `json:"request_id"`
+	}
+	resp, err := http.Get(srv.URL + "/products/5689919121?retailer=walmart&async=true")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&accepted)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || accepted.RequestID == "" {
+		t.Fatalf("async request = %d %+v, want 202 with a request id", resp.StatusCode, accepted)
+	}
+
+	var statuses []int
+	for range 4 {
+		resp, err := http.Get(srv.URL + "/requests/" + accepted.RequestID)
+		if err != nil {
+			t.Fatalf("poll error = %v", err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	want := []int{http.StatusAccepted, http.StatusAccepted, http.StatusOK, http.StatusNotFound}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Fatalf("poll statuses = %v, want %v", statuses, want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "search"), 0o755)
+	os.WriteFile(filepath.Join(dir, "search", "walmart.json"), []byte(`[{"title": "Widget Deluxe", "price": 5, "url": "https://example.com/w"}]`), 0o644)
+
+	c, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	srv := httptest.NewServer(New(c))
+	defer srv.Close()
+	offers, err := price.NewWalmartSearcher(srv.URL+"/search/walmart").Search(context.Background(), "widget")
+	if err != nil || len(offers) != 1 || offers[0].Title != "Widget Deluxe" {
+		t.Errorf("Search() = %+v, %v", offers, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "search", "amazon.json"), []byte(`{not json`), 0o644)
+	if _, err := LoadDir(dir); err == nil {
+		t.Errorf("LoadDir should reject malformed seed data")
+	}
+}
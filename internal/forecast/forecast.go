@@ -0,0 +1,215 @@
+// Package forecast predicts where a listing's price is heading from its
+// recorded history, using exponential smoothing.
+//
+// Prices are reduced to one value per day, the day's lowest. With at least
+// two weeks of history the model is Holt-Winters with a damped trend and a
+// weekly season, so a listing that is regularly cheaper on certain days of the
+// week shows it; with less, it is Holt's damped trend alone. The smoothing
+// parameters are chosen by minimising the one-step-ahead error over the
+// history.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"savvyshopper/internal/history"
+)
+
+const (
+	// Period is the length of the season, in days.
+	Period = 7
+	// MinDays is the least history, in days, that can be forecast.
+	MinDays = 4
+	// damping shrinks the trend each day ahead, so a recent drift is not
+	// extrapolated indefinitely.
+	damping = 0.9
+	// z95 is the normal quantile for a 95% confidence band.
+	z95 = 1.96
+	// minSeasonality is the weekly swing, as a fraction of the price level,
+	// below which no day of the week is reported as cheapest.
+	minSeasonality = 0.005
+)
+
+// grid holds the candidate values of each smoothing parameter.
+var grid = []float64{0.05, 0.1, 0.2, 0.4, 0.6, 0.8}
+
+// ErrTooShort means the history covers fewer than MinDays days.
+var ErrTooShort = fmt.Errorf("need at least %d days of price history to forecast", MinDays)
+
+// Day is the price expected on one day, with its 95% confidence band.
+type Day struct {
+	Date  time.Time
+	Price float64
+	Low   float64
+	High  float64
+}
+
+// Forecast is the expected price over the coming days.
+type Forecast struct {
+	// Days holds one entry per day ahead, starting the day after the
+	// history ends.
+	Days []Day
+	// Low is the day with the lowest expected price.
+	Low Day
+	// Last is the last day of the history and its price.
+	Last Day
+	// History is how many days the forecast is based on.
+	History int
+	// Seasonal reports whether prices vary by day of the week, in which case
+	// Weekday is the day they tend to be lowest.
+	Seasonal bool
+	Weekday  time.Weekday
+}
+
+// Daily reduces points to the lowest price on each day, in UTC, from the
+// first day seen to the last. A day without a price repeats the one before.
+func Daily(points []history.Point) (start time.Time, prices []float64) {
+	if len(points) == 0 {
+		return time.Time{}, nil
+	}
+	points = append([]history.Point(nil), points...)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	start = day(points[0].Time)
+	n := int(day(points[len(points)-1].Time).Sub(start).Hours()/24) + 1
+	prices = make([]float64, n)
+	seen := make([]bool, n)
+	for _, p := range points {
+		i := int(day(p.Time).Sub(start).Hours() / 24)
+		if !seen[i] || p.Price < prices[i] {
+			prices[i], seen[i] = p.Price, true
+		}
+	}
+	for i := 1; i < n; i++ {
+		if !seen[i] {
+			prices[i] = prices[i-1]
+		}
+	}
+	return start, prices
+}
+
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// FromHistory forecasts the next days days from recorded points.
+func FromHistory(points []history.Point, days int) (Forecast, error) {
+	start, prices := Daily(points)
+	return Predict(start, prices, days)
+}
+
+// Predict forecasts the next days days of a daily price series whose first
+// value is for start.
+func Predict(start time.Time, prices []float64, days int) (Forecast, error) {
+	if days <= 0 {
+		return Forecast{}, errors.New("forecast days must be positive")
+	}
+	if len(prices) < MinDays {
+		return Forecast{}, ErrTooShort
+	}
+
+	seasonal := len(prices) >= 2*Period
+	gammas := []float64{0}
+	if seasonal {
+		gammas = grid
+	}
+	var best *model
+	for _, alpha := range grid {
+		for _, beta := range grid {
+			for _, gamma := range gammas {
+				m := fit(prices, alpha, beta, gamma, seasonal)
+				if best == nil || m.sse < best.sse {
+					best = m
+				}
+			}
+		}
+	}
+
+	n := len(prices)
+	f := Forecast{
+		History: n,
+		Last:    Day{Date: start.AddDate(0, 0, n-1), Price: prices[n-1], Low: prices[n-1], High: prices[n-1]},
+	}
+	sigma := math.Sqrt(best.sse / float64(best.count))
+	trend := 0.0
+	for h := 1; h <= days; h++ {
+		trend = trend*damping + best.trend*damping
+		t := n + h - 1
+		price := max(best.level+trend+best.season[t%Period], 0)
+		band := z95 * sigma * math.Sqrt(1+float64(h-1)*best.alpha*best.alpha)
+		d := Day{Date: start.AddDate(0, 0, t), Price: price, Low: max(price-band, 0), High: price + band}
+		f.Days = append(f.Days, d)
+		if h == 1 || d.Price < f.Low.Price {
+			f.Low = d
+		}
+	}
+
+	if seasonal {
+		lo, hi := 0, 0
+		for i, s := range best.season {
+			if s < best.season[lo] {
+				lo = i
+			}
+			if s > best.season[hi] {
+				hi = i
+			}
+		}
+		if best.level > 0 && (best.season[hi]-best.season[lo])/best.level >= minSeasonality {
+			f.Seasonal = true
+			f.Weekday = start.AddDate(0, 0, lo).Weekday()
+		}
+	}
+	return f, nil
+}
+
+// model is the state of the smoothing after the whole history. season is
+// indexed by day number modulo Period; it is all zero without seasonality.
+type model struct {
+	alpha        float64
+	level, trend float64
+	season       [Period]float64
+	sse          float64
+	count        int
+}
+
+// fit runs additive Holt-Winters with a damped trend over prices, summing the
+// squared one-step-ahead errors. Without seasonality gamma is ignored.
+func fit(prices []float64, alpha, beta, gamma float64, seasonal bool) *model {
+	m := &model{alpha: alpha}
+	first := 1
+	if seasonal {
+		var a, b float64
+		for i := range Period {
+			a += prices[i]
+			b += prices[Period+i]
+		}
+		a, b = a/Period, b/Period
+		m.level, m.trend = a, (b-a)/Period
+		for i := range Period {
+			m.season[i] = prices[i] - a
+		}
+		first = Period
+	} else {
+		m.level = prices[0]
+	}
+
+	for t := first; t < len(prices); t++ {
+		s := m.season[t%Period]
+		err := prices[t] - (m.level + damping*m.trend + s)
+		m.sse += err * err
+		m.count++
+
+		level := alpha*(prices[t]-s) + (1-alpha)*(m.level+damping*m.trend)
+		m.trend = beta*(level-m.level) + (1-beta)*damping*m.trend
+		if seasonal {
+			m.season[t%Period] = gamma*(prices[t]-level) + (1-gamma)*s
+		}
+		m.level = level
+	}
+	return m
+}
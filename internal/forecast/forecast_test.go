@@ -0,0 +1,113 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"savvyshopper/internal/history"
+)
+
+// start is a Monday.
+var start = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+func TestPredict_WeeklyDip(t *testing.T) {
+	// Eight weeks at $100 with a $10 dip every Tuesday and a little noise.
+	prices := make([]float64, 56)
+	for i := range prices {
+		prices[i] = 100 + 0.5*math.Sin(float64(i)*1.3)
+		if start.AddDate(0, 0, i).Weekday() == time.Tuesday {
+			prices[i] -= 10
+		}
+	}
+
+	f, err := Predict(start, prices, 14)
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if len(f.Days) != 14 || !f.Days[0].Date.Equal(start.AddDate(0, 0, 56)) {
+		t.Fatalf("Days = %d starting %v, want 14 starting the day after the history", len(f.Days), f.Days[0].Date)
+	}
+	if !f.Seasonal || f.Weekday != time.Tuesday {
+		t.Errorf("Seasonal, Weekday = %v, %v, want true, Tuesday", f.Seasonal, f.Weekday)
+	}
+	if f.Low.Date.Weekday() != time.Tuesday || math.Abs(f.Low.Price-90) > 1.5 {
+		t.Errorf("Low = %.2f on %v, want about 90 on a Tuesday", f.Low.Price, f.Low.Date.Weekday())
+	}
+	if f.Low.Low > 90 || f.Low.High < 90 {
+		t.Errorf("Low band = %.2f-%.2f, want it to contain 90", f.Low.Low, f.Low.High)
+	}
+	for _, d := range f.Days {
+		if d.Date.Weekday() != time.Tuesday && math.Abs(d.Price-100) > 1.5 {
+			t.Errorf("%v price = %.2f, want about 100", d.Date.Weekday(), d.Price)
+		}
+	}
+}
+
+func TestPredict_Trend(t *testing.T) {
+	// A steady $1 a day decline, too short for seasonality.
+	prices := []float64{120, 119, 118, 117, 116, 115, 114, 113, 112, 111}
+	f, err := Predict(start, prices, 7)
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if f.Seasonal {
+		t.Error("Seasonal = true, want false with under two weeks of history")
+	}
+	if f.Low.Price >= 111 || f.Low.Price < 104 {
+		t.Errorf("Low = %.2f, want it to keep falling but flatten out", f.Low.Price)
+	}
+	if !f.Low.Date.Equal(f.Days[6].Date) {
+		t.Errorf("Low on %v, want the last day", f.Low.Date)
+	}
+	for i := 1; i < len(f.Days); i++ {
+		if f.Days[i].High-f.Days[i].Low < f.Days[i-1].High-f.Days[i-1].Low {
+			t.Errorf("band narrows on day %d", i+1)
+		}
+	}
+}
+
+func TestPredict_Flat(t *testing.T) {
+	prices := make([]float64, 21)
+	for i := range prices {
+		prices[i] = 49.99
+	}
+	f, err := Predict(start, prices, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Seasonal || math.Abs(f.Low.Price-49.99) > 0.01 || f.Low.High-f.Low.Low > 0.01 {
+		t.Errorf("Forecast = %+v, want a flat 49.99 with no band", f.Low)
+	}
+}
+
+func TestPredict_TooShort(t *testing.T) {
+	if _, err := Predict(start, []float64{10, 11, 12}, 7); !errors.Is(err, ErrTooShort) {
+		t.Errorf("Predict() error = %v, want ErrTooShort", err)
+	}
+	if _, err := Predict(start, []float64{10, 11, 12, 13}, 0); err == nil {
+		t.Error("Predict(0 days) error = nil, want error")
+	}
+}
+
+func TestDaily(t *testing.T) {
+	at := func(days, hours int, price float64) history.Point {
+		return history.Point{Time: start.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour), Price: price}
+	}
+	// Out of order, two prices on day 0 and nothing on day 2.
+	got0, prices := Daily([]history.Point{at(3, 9, 7), at(0, 20, 10), at(1, 1, 9), at(0, 8, 12)})
+	want := []float64{10, 9, 9, 7}
+	if !got0.Equal(start) {
+		t.Errorf("start = %v, want %v", got0, start)
+	}
+	if len(prices) != len(want) {
+		t.Fatalf("Daily() = %v, want %v", prices, want)
+	}
+	for i := range want {
+		if prices[i] != want[i] {
+			t.Errorf("Daily() = %v, want %v", prices, want)
+			break
+		}
+	}
+}
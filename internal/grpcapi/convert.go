@@ -0,0 +1,87 @@
+package grpcapi
+
+import (
+	"savvyshopper/domain"
+	"savvyshopper/internal/grpcapi/pricesearchpb"
+	"savvyshopper/internal/price"
+)
+
+var eventKinds = map[price.EventKind]pricesearchpb.SearchEvent_Kind{
+	price.EventStarted: pricesearchpb.SearchEvent_KIND_STARTED,
+	price.EventOffers:  pricesearchpb.SearchEvent_KIND_OFFERS,
+	price.EventFailed:  pricesearchpb.SearchEvent_KIND_FAILED,
+	price.EventDone:    pricesearchpb.SearchEvent_KIND_DONE,
+}
+
+func offerPB(o domain.Offer) *pricesearchpb.Offer {
+	return &pricesearchpb.Offer{
+		Title:          o.Title,
+		Price:          o.Price,
+		Url:            o.URL,
+		Retailer:       string(o.Retailer),
+		ProductId:      o.ProductID,
+		Seller:         o.Seller,
+		SellerRating:   o.SellerRating,
+		SellerRatings:  int32(o.SellerRatings),
+		Fulfillment:    string(o.Fulfillment),
+		Gtin:           o.GTIN,
+		Model:          o.Model,
+		Shipping:       o.Shipping,
+		Landed:         o.Landed(),
+		Rating:         o.Rating,
+		Reviews:        int32(o.Reviews),
+		Condition:      string(o.Condition),
+		Prime:          o.Prime,
+		OutOfStock:     o.OutOfStock,
+		Relevance:      o.Relevance,
+		Currency:       o.Currency(),
+		Tax:            o.Tax,
+		ListPrice:      o.ListPrice,
+		Savings:        o.Savings,
+		EffectivePrice: o.EffectivePrice(),
+	}
+}
+
+func offersPB(offers []domain.Offer) []*pricesearchpb.Offer {
+	out := make([]*pricesearchpb.Offer, len(offers))
+	for i, o := range offers {
+		out[i] = offerPB(o)
+	}
+	return out
+}
+
+func eventPB(ev price.SearchEvent) *pricesearchpb.SearchEvent {
+	out := &pricesearchpb.SearchEvent{
+		Kind:      eventKinds[ev.Kind],
+		Retailer:  string(ev.Retailer),
+		Offers:    offersPB(ev.Offers),
+		ElapsedMs: ev.Elapsed.Milliseconds(),
+	}
+	if ev.Err != nil {
+		out.Error = ev.Err.Error()
+	}
+	return out
+}
+
+func productPB(p domain.ProductDetails) *pricesearchpb.Product {
+	out := &pricesearchpb.Product{
+		Retailer:    string(p.Retailer),
+		ProductId:   p.ProductID,
+		Title:       p.Title,
+		Brand:       p.Brand,
+		Description: p.Description,
+		Url:         p.URL,
+		Price:       p.Price,
+		Rating:      p.Rating,
+		Reviews:     int32(p.Reviews),
+		Gtin:        p.GTIN,
+		Model:       p.Model,
+		OutOfStock:  p.OutOfStock,
+		Images:      p.Images,
+		Offers:      offersPB(p.Offers),
+	}
+	for _, v := range p.Variants {
+		out.Variants = append(out.Variants, &pricesearchpb.Variant{ProductId: v.ProductID, Specifics: v.Specifics})
+	}
+	return out
+}
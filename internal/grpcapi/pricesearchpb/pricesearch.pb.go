@@ -0,0 +1,1119 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: savvyshopper/v1/pricesearch.proto
+
+package pricesearchpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchEvent_Kind int32
+
+const (
+	SearchEvent_KIND_UNSPECIFIED SearchEvent_Kind = 0
+	SearchEvent_KIND_STARTED     SearchEvent_Kind = 1
+	SearchEvent_KIND_OFFERS      SearchEvent_Kind = 2
+	SearchEvent_KIND_FAILED      SearchEvent_Kind = 3
+	SearchEvent_KIND_DONE        SearchEvent_Kind = 4
+)
+
+// Enum value maps for SearchEvent_Kind.
+var (
+	SearchEvent_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_STARTED",
+		2: "KIND_OFFERS",
+		3: "KIND_FAILED",
+		4: "KIND_DONE",
+	}
+	SearchEvent_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_STARTED":     1,
+		"KIND_OFFERS":      2,
+		"KIND_FAILED":      3,
+		"KIND_DONE":        4,
+	}
+)
+
+func (x SearchEvent_Kind) Enum() *SearchEvent_Kind {
+	p := new(SearchEvent_Kind)
+	*p = x
+	return p
+}
+
+func (x SearchEvent_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SearchEvent_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_savvyshopper_v1_pricesearch_proto_enumTypes[0].Descriptor()
+}
+
+func (SearchEvent_Kind) Type() protoreflect.EnumType {
+	return &file_savvyshopper_v1_pricesearch_proto_enumTypes[0]
+}
+
+func (x SearchEvent_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SearchEvent_Kind.Descriptor instead.
+func (SearchEvent_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{3, 0}
+}
+
+// SearchRequest mirrors the CLI search flags. Zero values mean no constraint.
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// One of price, rating, reviews, relevance, retailer. Landed cost and deal
+	// scores are only worked out by the CLI.
+	Sort     string  `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc     bool    `protobuf:"varint,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	MinPrice float64 `protobuf:"fixed64,4,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice float64 `protobuf:"fixed64,5,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	// One of new, used, refurbished.
+	Condition string  `protobuf:"bytes,6,opt,name=condition,proto3" json:"condition,omitempty"`
+	InStock   bool    `protobuf:"varint,7,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	PrimeOnly bool    `protobuf:"varint,8,opt,name=prime_only,json=primeOnly,proto3" json:"prime_only,omitempty"`
+	MinRating float64 `protobuf:"fixed64,9,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	// One of retailer, seller.
+	Fulfillment   string   `protobuf:"bytes,10,opt,name=fulfillment,proto3" json:"fulfillment,omitempty"`
+	MinRelevance  float64  `protobuf:"fixed64,11,opt,name=min_relevance,json=minRelevance,proto3" json:"min_relevance,omitempty"`
+	Exclude       []string `protobuf:"bytes,12,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *SearchRequest) GetMinPrice() float64 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetMaxPrice() float64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *SearchRequest) GetPrimeOnly() bool {
+	if x != nil {
+		return x.PrimeOnly
+	}
+	return false
+}
+
+func (x *SearchRequest) GetMinRating() float64 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetFulfillment() string {
+	if x != nil {
+		return x.Fulfillment
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMinRelevance() float64 {
+	if x != nil {
+		return x.MinRelevance
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offers        []*Offer               `protobuf:"bytes,1,rep,name=offers,proto3" json:"offers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchResponse) GetOffers() []*Offer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+type Offer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Retailer      string                 `protobuf:"bytes,4,opt,name=retailer,proto3" json:"retailer,omitempty"`
+	ProductId     string                 `protobuf:"bytes,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Seller        string                 `protobuf:"bytes,6,opt,name=seller,proto3" json:"seller,omitempty"`
+	SellerRating  float64                `protobuf:"fixed64,7,opt,name=seller_rating,json=sellerRating,proto3" json:"seller_rating,omitempty"`
+	SellerRatings int32                  `protobuf:"varint,8,opt,name=seller_ratings,json=sellerRatings,proto3" json:"seller_ratings,omitempty"`
+	Fulfillment   string                 `protobuf:"bytes,9,opt,name=fulfillment,proto3" json:"fulfillment,omitempty"`
+	Gtin          string                 `protobuf:"bytes,10,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Model         string                 `protobuf:"bytes,11,opt,name=model,proto3" json:"model,omitempty"`
+	Shipping      float64                `protobuf:"fixed64,12,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	Landed        float64                `protobuf:"fixed64,13,opt,name=landed,proto3" json:"landed,omitempty"`
+	Rating        float64                `protobuf:"fixed64,14,opt,name=rating,proto3" json:"rating,omitempty"`
+	Reviews       int32                  `protobuf:"varint,15,opt,name=reviews,proto3" json:"reviews,omitempty"`
+	Condition     string                 `protobuf:"bytes,16,opt,name=condition,proto3" json:"condition,omitempty"`
+	Prime         bool                   `protobuf:"varint,17,opt,name=prime,proto3" json:"prime,omitempty"`
+	OutOfStock    bool                   `protobuf:"varint,18,opt,name=out_of_stock,json=outOfStock,proto3" json:"out_of_stock,omitempty"`
+	Relevance     float64                `protobuf:"fixed64,19,opt,name=relevance,proto3" json:"relevance,omitempty"`
+	// ISO 4217 code of price, shipping and landed, in the marketplace's local
+	// currency.
+	Currency string `protobuf:"bytes,20,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Estimated sales tax, included in landed.
+	Tax float64 `protobuf:"fixed64,21,opt,name=tax,proto3" json:"tax,omitempty"`
+	// Pre-sale reference price; zero when not on sale.
+	ListPrice float64 `protobuf:"fixed64,22,opt,name=list_price,json=listPrice,proto3" json:"list_price,omitempty"`
+	// What the caller's promotions take off price.
+	Savings float64 `protobuf:"fixed64,23,opt,name=savings,proto3" json:"savings,omitempty"`
+	// price minus savings, included in landed.
+	EffectivePrice float64 `protobuf:"fixed64,24,opt,name=effective_price,json=effectivePrice,proto3" json:"effective_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Offer) Reset() {
+	*x = Offer{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Offer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offer) ProtoMessage() {}
+
+func (x *Offer) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offer.ProtoReflect.Descriptor instead.
+func (*Offer) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Offer) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Offer) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Offer) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Offer) GetRetailer() string {
+	if x != nil {
+		return x.Retailer
+	}
+	return ""
+}
+
+func (x *Offer) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Offer) GetSeller() string {
+	if x != nil {
+		return x.Seller
+	}
+	return ""
+}
+
+func (x *Offer) GetSellerRating() float64 {
+	if x != nil {
+		return x.SellerRating
+	}
+	return 0
+}
+
+func (x *Offer) GetSellerRatings() int32 {
+	if x != nil {
+		return x.SellerRatings
+	}
+	return 0
+}
+
+func (x *Offer) GetFulfillment() string {
+	if x != nil {
+		return x.Fulfillment
+	}
+	return ""
+}
+
+func (x *Offer) GetGtin() string {
+	if x != nil {
+		return x.Gtin
+	}
+	return ""
+}
+
+func (x *Offer) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Offer) GetShipping() float64 {
+	if x != nil {
+		return x.Shipping
+	}
+	return 0
+}
+
+func (x *Offer) GetLanded() float64 {
+	if x != nil {
+		return x.Landed
+	}
+	return 0
+}
+
+func (x *Offer) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Offer) GetReviews() int32 {
+	if x != nil {
+		return x.Reviews
+	}
+	return 0
+}
+
+func (x *Offer) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *Offer) GetPrime() bool {
+	if x != nil {
+		return x.Prime
+	}
+	return false
+}
+
+func (x *Offer) GetOutOfStock() bool {
+	if x != nil {
+		return x.OutOfStock
+	}
+	return false
+}
+
+func (x *Offer) GetRelevance() float64 {
+	if x != nil {
+		return x.Relevance
+	}
+	return 0
+}
+
+func (x *Offer) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Offer) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *Offer) GetListPrice() float64 {
+	if x != nil {
+		return x.ListPrice
+	}
+	return 0
+}
+
+func (x *Offer) GetSavings() float64 {
+	if x != nil {
+		return x.Savings
+	}
+	return 0
+}
+
+func (x *Offer) GetEffectivePrice() float64 {
+	if x != nil {
+		return x.EffectivePrice
+	}
+	return 0
+}
+
+type SearchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          SearchEvent_Kind       `protobuf:"varint,1,opt,name=kind,proto3,enum=savvyshopper.v1.SearchEvent_Kind" json:"kind,omitempty"`
+	Retailer      string                 `protobuf:"bytes,2,opt,name=retailer,proto3" json:"retailer,omitempty"`
+	Offers        []*Offer               `protobuf:"bytes,3,rep,name=offers,proto3" json:"offers,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	ElapsedMs     int64                  `protobuf:"varint,5,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEvent) Reset() {
+	*x = SearchEvent{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchEvent) ProtoMessage() {}
+
+func (x *SearchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchEvent.ProtoReflect.Descriptor instead.
+func (*SearchEvent) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchEvent) GetKind() SearchEvent_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return SearchEvent_KIND_UNSPECIFIED
+}
+
+func (x *SearchEvent) GetRetailer() string {
+	if x != nil {
+		return x.Retailer
+	}
+	return ""
+}
+
+func (x *SearchEvent) GetOffers() []*Offer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+func (x *SearchEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SearchEvent) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+type GetProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// An Amazon or Walmart product URL, an ASIN, or a Walmart item ID.
+	Ref           string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetProductRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Specifics     map[string]string      `protobuf:"bytes,2,rep,name=specifics,proto3" json:"specifics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Variant) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Variant) GetSpecifics() map[string]string {
+	if x != nil {
+		return x.Specifics
+	}
+	return nil
+}
+
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Retailer      string                 `protobuf:"bytes,1,opt,name=retailer,proto3" json:"retailer,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Brand         string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	Price         float64                `protobuf:"fixed64,7,opt,name=price,proto3" json:"price,omitempty"`
+	Rating        float64                `protobuf:"fixed64,8,opt,name=rating,proto3" json:"rating,omitempty"`
+	Reviews       int32                  `protobuf:"varint,9,opt,name=reviews,proto3" json:"reviews,omitempty"`
+	Gtin          string                 `protobuf:"bytes,10,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Model         string                 `protobuf:"bytes,11,opt,name=model,proto3" json:"model,omitempty"`
+	OutOfStock    bool                   `protobuf:"varint,12,opt,name=out_of_stock,json=outOfStock,proto3" json:"out_of_stock,omitempty"`
+	Images        []string               `protobuf:"bytes,13,rep,name=images,proto3" json:"images,omitempty"`
+	Variants      []*Variant             `protobuf:"bytes,14,rep,name=variants,proto3" json:"variants,omitempty"`
+	Offers        []*Offer               `protobuf:"bytes,15,rep,name=offers,proto3" json:"offers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Product) GetRetailer() string {
+	if x != nil {
+		return x.Retailer
+	}
+	return ""
+}
+
+func (x *Product) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Product) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Product) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Product) GetReviews() int32 {
+	if x != nil {
+		return x.Reviews
+	}
+	return 0
+}
+
+func (x *Product) GetGtin() string {
+	if x != nil {
+		return x.Gtin
+	}
+	return ""
+}
+
+func (x *Product) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Product) GetOutOfStock() bool {
+	if x != nil {
+		return x.OutOfStock
+	}
+	return false
+}
+
+func (x *Product) GetImages() []string {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *Product) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *Product) GetOffers() []*Offer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Search *SearchRequest         `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	// Seconds between searches; the server enforces a minimum.
+	IntervalSeconds int64 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetSearch() *SearchRequest {
+	if x != nil {
+		return x.Search
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type WatchUpdate struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Offers []*Offer               `protobuf:"bytes,1,rep,name=offers,proto3" json:"offers,omitempty"`
+	// The cheapest offer by the request's sort order, i.e. offers[0].
+	Best          *Offer `protobuf:"bytes,2,opt,name=best,proto3" json:"best,omitempty"`
+	CheckedAtUnix int64  `protobuf:"varint,3,opt,name=checked_at_unix,json=checkedAtUnix,proto3" json:"checked_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUpdate) Reset() {
+	*x = WatchUpdate{}
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUpdate) ProtoMessage() {}
+
+func (x *WatchUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_savvyshopper_v1_pricesearch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUpdate.ProtoReflect.Descriptor instead.
+func (*WatchUpdate) Descriptor() ([]byte, []int) {
+	return file_savvyshopper_v1_pricesearch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchUpdate) GetOffers() []*Offer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+func (x *WatchUpdate) GetBest() *Offer {
+	if x != nil {
+		return x.Best
+	}
+	return nil
+}
+
+func (x *WatchUpdate) GetCheckedAtUnix() int64 {
+	if x != nil {
+		return x.CheckedAtUnix
+	}
+	return 0
+}
+
+var File_savvyshopper_v1_pricesearch_proto protoreflect.FileDescriptor
+
+const file_savvyshopper_v1_pricesearch_proto_rawDesc = "" +
+	"\n" +
+	"!savvyshopper/v1/pricesearch.proto\x12\x0fsavvyshopper.v1\"\xdf\x02\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\bR\x04desc\x12\x1b\n" +
+	"\tmin_price\x18\x04 \x01(\x01R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x05 \x01(\x01R\bmaxPrice\x12\x1c\n" +
+	"\tcondition\x18\x06 \x01(\tR\tcondition\x12\x19\n" +
+	"\bin_stock\x18\a \x01(\bR\ainStock\x12\x1d\n" +
+	"\n" +
+	"prime_only\x18\b \x01(\bR\tprimeOnly\x12\x1d\n" +
+	"\n" +
+	"min_rating\x18\t \x01(\x01R\tminRating\x12 \n" +
+	"\vfulfillment\x18\n" +
+	" \x01(\tR\vfulfillment\x12#\n" +
+	"\rmin_relevance\x18\v \x01(\x01R\fminRelevance\x12\x18\n" +
+	"\aexclude\x18\f \x03(\tR\aexclude\"@\n" +
+	"\x0eSearchResponse\x12.\n" +
+	"\x06offers\x18\x01 \x03(\v2\x16.savvyshopper.v1.OfferR\x06offers\"\x9a\x05\n" +
+	"\x05Offer\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x1a\n" +
+	"\bretailer\x18\x04 \x01(\tR\bretailer\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x05 \x01(\tR\tproductId\x12\x16\n" +
+	"\x06seller\x18\x06 \x01(\tR\x06seller\x12#\n" +
+	"\rseller_rating\x18\a \x01(\x01R\fsellerRating\x12%\n" +
+	"\x0eseller_ratings\x18\b \x01(\x05R\rsellerRatings\x12 \n" +
+	"\vfulfillment\x18\t \x01(\tR\vfulfillment\x12\x12\n" +
+	"\x04gtin\x18\n" +
+	" \x01(\tR\x04gtin\x12\x14\n" +
+	"\x05model\x18\v \x01(\tR\x05model\x12\x1a\n" +
+	"\bshipping\x18\f \x01(\x01R\bshipping\x12\x16\n" +
+	"\x06landed\x18\r \x01(\x01R\x06landed\x12\x16\n" +
+	"\x06rating\x18\x0e \x01(\x01R\x06rating\x12\x18\n" +
+	"\areviews\x18\x0f \x01(\x05R\areviews\x12\x1c\n" +
+	"\tcondition\x18\x10 \x01(\tR\tcondition\x12\x14\n" +
+	"\x05prime\x18\x11 \x01(\bR\x05prime\x12 \n" +
+	"\fout_of_stock\x18\x12 \x01(\bR\n" +
+	"outOfStock\x12\x1c\n" +
+	"\trelevance\x18\x13 \x01(\x01R\trelevance\x12\x1a\n" +
+	"\bcurrency\x18\x14 \x01(\tR\bcurrency\x12\x10\n" +
+	"\x03tax\x18\x15 \x01(\x01R\x03tax\x12\x1d\n" +
+	"\n" +
+	"list_price\x18\x16 \x01(\x01R\tlistPrice\x12\x18\n" +
+	"\asavings\x18\x17 \x01(\x01R\asavings\x12'\n" +
+	"\x0feffective_price\x18\x18 \x01(\x01R\x0eeffectivePrice\"\xa6\x02\n" +
+	"\vSearchEvent\x125\n" +
+	"\x04kind\x18\x01 \x01(\x0e2!.savvyshopper.v1.SearchEvent.KindR\x04kind\x12\x1a\n" +
+	"\bretailer\x18\x02 \x01(\tR\bretailer\x12.\n" +
+	"\x06offers\x18\x03 \x03(\v2\x16.savvyshopper.v1.OfferR\x06offers\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\x05 \x01(\x03R\telapsedMs\"_\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fKIND_STARTED\x10\x01\x12\x0f\n" +
+	"\vKIND_OFFERS\x10\x02\x12\x0f\n" +
+	"\vKIND_FAILED\x10\x03\x12\r\n" +
+	"\tKIND_DONE\x10\x04\"%\n" +
+	"\x11GetProductRequest\x12\x10\n" +
+	"\x03ref\x18\x01 \x01(\tR\x03ref\"\xad\x01\n" +
+	"\aVariant\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12E\n" +
+	"\tspecifics\x18\x02 \x03(\v2'.savvyshopper.v1.Variant.SpecificsEntryR\tspecifics\x1a<\n" +
+	"\x0eSpecificsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb6\x03\n" +
+	"\aProduct\x12\x1a\n" +
+	"\bretailer\x18\x01 \x01(\tR\bretailer\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12\x14\n" +
+	"\x05price\x18\a \x01(\x01R\x05price\x12\x16\n" +
+	"\x06rating\x18\b \x01(\x01R\x06rating\x12\x18\n" +
+	"\areviews\x18\t \x01(\x05R\areviews\x12\x12\n" +
+	"\x04gtin\x18\n" +
+	" \x01(\tR\x04gtin\x12\x14\n" +
+	"\x05model\x18\v \x01(\tR\x05model\x12 \n" +
+	"\fout_of_stock\x18\f \x01(\bR\n" +
+	"outOfStock\x12\x16\n" +
+	"\x06images\x18\r \x03(\tR\x06images\x124\n" +
+	"\bvariants\x18\x0e \x03(\v2\x18.savvyshopper.v1.VariantR\bvariants\x12.\n" +
+	"\x06offers\x18\x0f \x03(\v2\x16.savvyshopper.v1.OfferR\x06offers\"q\n" +
+	"\fWatchRequest\x126\n" +
+	"\x06search\x18\x01 \x01(\v2\x1e.savvyshopper.v1.SearchRequestR\x06search\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x03R\x0fintervalSeconds\"\x91\x01\n" +
+	"\vWatchUpdate\x12.\n" +
+	"\x06offers\x18\x01 \x03(\v2\x16.savvyshopper.v1.OfferR\x06offers\x12*\n" +
+	"\x04best\x18\x02 \x01(\v2\x16.savvyshopper.v1.OfferR\x04best\x12&\n" +
+	"\x0fchecked_at_unix\x18\x03 \x01(\x03R\rcheckedAtUnix2\xbc\x02\n" +
+	"\vPriceSearch\x12I\n" +
+	"\x06Search\x12\x1e.savvyshopper.v1.SearchRequest\x1a\x1f.savvyshopper.v1.SearchResponse\x12N\n" +
+	"\fStreamSearch\x12\x1e.savvyshopper.v1.SearchRequest\x1a\x1c.savvyshopper.v1.SearchEvent0\x01\x12J\n" +
+	"\n" +
+	"GetProduct\x12\".savvyshopper.v1.GetProductRequest\x1a\x18.savvyshopper.v1.Product\x12F\n" +
+	"\x05Watch\x12\x1d.savvyshopper.v1.WatchRequest\x1a\x1c.savvyshopper.v1.WatchUpdate0\x01B-Z+savvyshopper/internal/grpcapi/pricesearchpbb\x06proto3"
+
+var (
+	file_savvyshopper_v1_pricesearch_proto_rawDescOnce sync.Once
+	file_savvyshopper_v1_pricesearch_proto_rawDescData []byte
+)
+
+func file_savvyshopper_v1_pricesearch_proto_rawDescGZIP() []byte {
+	file_savvyshopper_v1_pricesearch_proto_rawDescOnce.Do(func() {
+		file_savvyshopper_v1_pricesearch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_savvyshopper_v1_pricesearch_proto_rawDesc), len(file_savvyshopper_v1_pricesearch_proto_rawDesc)))
+	})
+	return file_savvyshopper_v1_pricesearch_proto_rawDescData
+}
+
+var file_savvyshopper_v1_pricesearch_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_savvyshopper_v1_pricesearch_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_savvyshopper_v1_pricesearch_proto_goTypes = []any{
+	(SearchEvent_Kind)(0),     // 0: savvyshopper.v1.SearchEvent.Kind
+	(*SearchRequest)(nil),     // 1: savvyshopper.v1.SearchRequest
+	(*SearchResponse)(nil),    // 2: savvyshopper.v1.SearchResponse
+	(*Offer)(nil),             // 3: savvyshopper.v1.Offer
+	(*SearchEvent)(nil),       // 4: savvyshopper.v1.SearchEvent
+	(*GetProductRequest)(nil), // 5: savvyshopper.v1.GetProductRequest
+	(*Variant)(nil),           // 6: savvyshopper.v1.Variant
+	(*Product)(nil),           // 7: savvyshopper.v1.Product
+	(*WatchRequest)(nil),      // 8: savvyshopper.v1.WatchRequest
+	(*WatchUpdate)(nil),       // 9: savvyshopper.v1.WatchUpdate
+	nil,                       // 10: savvyshopper.v1.Variant.SpecificsEntry
+}
+var file_savvyshopper_v1_pricesearch_proto_depIdxs = []int32{
+	3,  // 0: savvyshopper.v1.SearchResponse.offers:type_name -> savvyshopper.v1.Offer
+	0,  // 1: savvyshopper.v1.SearchEvent.kind:type_name -> savvyshopper.v1.SearchEvent.Kind
+	3,  // 2: savvyshopper.v1.SearchEvent.offers:type_name -> savvyshopper.v1.Offer
+	10, // 3: savvyshopper.v1.Variant.specifics:type_name -> savvyshopper.v1.Variant.SpecificsEntry
+	6,  // 4: savvyshopper.v1.Product.variants:type_name -> savvyshopper.v1.Variant
+	3,  // 5: savvyshopper.v1.Product.offers:type_name -> savvyshopper.v1.Offer
+	1,  // 6: savvyshopper.v1.WatchRequest.search:type_name -> savvyshopper.v1.SearchRequest
+	3,  // 7: savvyshopper.v1.WatchUpdate.offers:type_name -> savvyshopper.v1.Offer
+	3,  // 8: savvyshopper.v1.WatchUpdate.best:type_name -> savvyshopper.v1.Offer
+	1,  // 9: savvyshopper.v1.PriceSearch.Search:input_type -> savvyshopper.v1.SearchRequest
+	1,  // 10: savvyshopper.v1.PriceSearch.StreamSearch:input_type -> savvyshopper.v1.SearchRequest
+	5,  // 11: savvyshopper.v1.PriceSearch.GetProduct:input_type -> savvyshopper.v1.GetProductRequest
+	8,  // 12: savvyshopper.v1.PriceSearch.Watch:input_type -> savvyshopper.v1.WatchRequest
+	2,  // 13: savvyshopper.v1.PriceSearch.Search:output_type -> savvyshopper.v1.SearchResponse
+	4,  // 14: savvyshopper.v1.PriceSearch.StreamSearch:output_type -> savvyshopper.v1.SearchEvent
+	7,  // 15: savvyshopper.v1.PriceSearch.GetProduct:output_type -> savvyshopper.v1.Product
+	9,  // 16: savvyshopper.v1.PriceSearch.Watch:output_type -> savvyshopper.v1.WatchUpdate
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_savvyshopper_v1_pricesearch_proto_init() }
+func file_savvyshopper_v1_pricesearch_proto_init() {
+	if File_savvyshopper_v1_pricesearch_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_savvyshopper_v1_pricesearch_proto_rawDesc), len(file_savvyshopper_v1_pricesearch_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_savvyshopper_v1_pricesearch_proto_goTypes,
+		DependencyIndexes: file_savvyshopper_v1_pricesearch_proto_depIdxs,
+		EnumInfos:         file_savvyshopper_v1_pricesearch_proto_enumTypes,
+		MessageInfos:      file_savvyshopper_v1_pricesearch_proto_msgTypes,
+	}.Build()
+	File_savvyshopper_v1_pricesearch_proto = out.File
+	file_savvyshopper_v1_pricesearch_proto_goTypes = nil
+	file_savvyshopper_v1_pricesearch_proto_depIdxs = nil
+}
@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: savvyshopper/v1/pricesearch.proto
+
+package pricesearchpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PriceSearch_Search_FullMethodName       = "/savvyshopper.v1.PriceSearch/Search"
+	PriceSearch_StreamSearch_FullMethodName = "/savvyshopper.v1.PriceSearch/StreamSearch"
+	PriceSearch_GetProduct_FullMethodName   = "/savvyshopper.v1.PriceSearch/GetProduct"
+	PriceSearch_Watch_FullMethodName        = "/savvyshopper.v1.PriceSearch/Watch"
+)
+
+// PriceSearchClient is the client API for PriceSearch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PriceSearch compares product prices across retailers.
+type PriceSearchClient interface {
+	// Search queries every retailer and returns the merged offers.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// StreamSearch reports each retailer's status and offers as they arrive.
+	StreamSearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchEvent], error)
+	// GetProduct looks up a single listing with every seller's offer.
+	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error)
+	// Watch repeats a search on an interval and sends an update whenever the
+	// best offer changes.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchUpdate], error)
+}
+
+type priceSearchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPriceSearchClient(cc grpc.ClientConnInterface) PriceSearchClient {
+	return &priceSearchClient{cc}
+}
+
+func (c *priceSearchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, PriceSearch_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceSearchClient) StreamSearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceSearch_ServiceDesc.Streams[0], PriceSearch_StreamSearch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, SearchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceSearch_StreamSearchClient = grpc.ServerStreamingClient[SearchEvent]
+
+func (c *priceSearchClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Product)
+	err := c.cc.Invoke(ctx, PriceSearch_GetProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceSearchClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceSearch_ServiceDesc.Streams[1], PriceSearch_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceSearch_WatchClient = grpc.ServerStreamingClient[WatchUpdate]
+
+// PriceSearchServer is the server API for PriceSearch service.
+// All implementations must embed UnimplementedPriceSearchServer
+// for forward compatibility.
+//
+// PriceSearch compares product prices across retailers.
+type PriceSearchServer interface {
+	// Search queries every retailer and returns the merged offers.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// StreamSearch reports each retailer's status and offers as they arrive.
+	StreamSearch(*SearchRequest, grpc.ServerStreamingServer[SearchEvent]) error
+	// GetProduct looks up a single listing with every seller's offer.
+	GetProduct(context.Context, *GetProductRequest) (*Product, error)
+	// Watch repeats a search on an interval and sends an update whenever the
+	// best offer changes.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchUpdate]) error
+	mustEmbedUnimplementedPriceSearchServer()
+}
+
+// UnimplementedPriceSearchServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPriceSearchServer struct{}
+
+func (UnimplementedPriceSearchServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedPriceSearchServer) StreamSearch(*SearchRequest, grpc.ServerStreamingServer[SearchEvent]) error {
+	return status.Error(codes.Unimplemented, "method StreamSearch not implemented")
+}
+func (UnimplementedPriceSearchServer) GetProduct(context.Context, *GetProductRequest) (*Product, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedPriceSearchServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchUpdate]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedPriceSearchServer) mustEmbedUnimplementedPriceSearchServer() {}
+func (UnimplementedPriceSearchServer) testEmbeddedByValue()                     {}
+
+// UnsafePriceSearchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PriceSearchServer will
+// result in compilation errors.
+type UnsafePriceSearchServer interface {
+	mustEmbedUnimplementedPriceSearchServer()
+}
+
+func RegisterPriceSearchServer(s grpc.ServiceRegistrar, srv PriceSearchServer) {
+	// If the following call panics, it indicates UnimplementedPriceSearchServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PriceSearch_ServiceDesc, srv)
+}
+
+func _PriceSearch_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceSearchServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceSearch_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceSearchServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceSearch_StreamSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceSearchServer).StreamSearch(m, &grpc.GenericServerStream[SearchRequest, SearchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceSearch_StreamSearchServer = grpc.ServerStreamingServer[SearchEvent]
+
+func _PriceSearch_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceSearchServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceSearch_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceSearchServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceSearch_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceSearchServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceSearch_WatchServer = grpc.ServerStreamingServer[WatchUpdate]
+
+// PriceSearch_ServiceDesc is the grpc.ServiceDesc for PriceSearch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PriceSearch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "savvyshopper.v1.PriceSearch",
+	HandlerType: (*PriceSearchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _PriceSearch_Search_Handler,
+		},
+		{
+			MethodName: "GetProduct",
+			Handler:    _PriceSearch_GetProduct_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSearch",
+			Handler:       _PriceSearch_StreamSearch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _PriceSearch_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "savvyshopper/v1/pricesearch.proto",
+}
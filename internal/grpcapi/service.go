@@ -0,0 +1,264 @@
+// Package grpcapi exposes price search as the savvyshopper.v1.PriceSearch gRPC
+// service. It runs the same searchers as the CLI and the HTTP server.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/grpcapi/pricesearchpb"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/relevance"
+	"savvyshopper/internal/urlnorm"
+)
+
+// Watch intervals. A request without an interval uses defaultWatchInterval;
+// shorter ones are raised to minWatchInterval so a client cannot hammer the
+// retailers.
+var (
+	defaultWatchInterval = 5 * time.Minute
+	minWatchInterval     = 30 * time.Second
+)
+
+// ProductLookup fetches a listing with every seller's offer.
+// *price.ProductClient implements it.
+type ProductLookup interface {
+	Lookup(ctx context.Context, retailer domain.Retailer, productID string) (domain.ProductDetails, error)
+}
+
+// Service implements pricesearchpb.PriceSearchServer.
+type Service struct {
+	pricesearchpb.UnimplementedPriceSearchServer
+
+	searchers map[domain.Retailer]price.Searcher
+	// watched are the searchers Watch polls: searchers without the cache
+	// EnableCache adds, so a price change shows up on the next poll.
+	watched  map[domain.Retailer]price.Searcher
+	products ProductLookup
+}
+
+// New creates a Service that looks products up with products, or with a
+// price.ProductClient for the configured Zinc API if products is nil.
+// If searchersOpt is provided, it uses those searchers instead of the default ones.
+func New(products ProductLookup, searchersOpt ...map[domain.Retailer]price.Searcher) *Service {
+	s := &Service{
+		searchers: price.DefaultSearchers(),
+		products:  products,
+	}
+	if s.products == nil {
+		s.products = price.NewProductClient(config.Config{Zinc: config.DefaultZinc()}.WithEnv().Zinc.BaseURL)
+	}
+	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
+		s.searchers = searchersOpt[0]
+	}
+	s.watched = s.searchers
+	return s
+}
+
+// EnableCache reuses each retailer's results for the same query for ttl in
+// Search and StreamSearch. Watch always asks the retailers.
+func (s *Service) EnableCache(ttl time.Duration) {
+	s.searchers = price.NewCachedSearchers(s.watched, ttl)
+}
+
+// retailers returns the retailers the service searches.
+func (s *Service) retailers() []domain.Retailer {
+	return slices.Collect(maps.Keys(s.searchers))
+}
+
+// Search runs a search and returns every offer at once.
+func (s *Service) Search(ctx context.Context, req *pricesearchpb.SearchRequest) (*pricesearchpb.SearchResponse, error) {
+	params, err := newSearchParams(req, s.retailers())
+	if err != nil {
+		return nil, err
+	}
+	offers, err := s.search(ctx, s.searchers, params)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pricesearchpb.SearchResponse{Offers: offersPB(offers)}, nil
+}
+
+// StreamSearch sends each retailer's status and offers as they arrive. A
+// failed retailer is reported in a FAILED event rather than ending the stream;
+// the final DONE event carries the error of the search as a whole, if any.
+func (s *Service) StreamSearch(req *pricesearchpb.SearchRequest, stream pricesearchpb.PriceSearch_StreamSearchServer) error {
+	params, err := newSearchParams(req, s.retailers())
+	if err != nil {
+		return err
+	}
+	for ev := range price.StreamPrices(stream.Context(), params.query, s.searchers) {
+		if ev.Kind == price.EventOffers {
+			ev.Offers = params.apply(ev.Offers)
+		}
+		if err := stream.Send(eventPB(ev)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProduct looks up a listing by URL, ASIN or Walmart item ID.
+func (s *Service) GetProduct(ctx context.Context, req *pricesearchpb.GetProductRequest) (*pricesearchpb.Product, error) {
+	ref, err := urlnorm.ParseRef(req.GetRef())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	details, err := s.products.Lookup(ctx, ref.Retailer, ref.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return productPB(details), nil
+}
+
+// Watch repeats a search every interval and sends an update with the first
+// results and again whenever the best offer changes. Searches that fail with
+// a network error or find nothing are skipped; the watch ends when the client
+// cancels or the API key is rejected.
+func (s *Service) Watch(req *pricesearchpb.WatchRequest, stream pricesearchpb.PriceSearch_WatchServer) error {
+	params, err := newSearchParams(req.GetSearch(), s.retailers())
+	if err != nil {
+		return err
+	}
+	interval := time.Duration(req.GetIntervalSeconds()) * time.Second
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	interval = max(interval, minWatchInterval)
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var best *domain.Offer
+	for {
+		offers, err := s.search(ctx, s.watched, params)
+		switch {
+		case ctx.Err() != nil:
+			return statusError(ctx.Err())
+		case errors.Is(err, domain.ErrAuth):
+			return statusError(err)
+		case err == nil && (best == nil || changed(*best, offers[0])):
+			best = &offers[0]
+			update := &pricesearchpb.WatchUpdate{
+				Offers:        offersPB(offers),
+				Best:          offerPB(*best),
+				CheckedAtUnix: time.Now().Unix(),
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return statusError(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// search runs params against searchers and refines the results, returning
+// domain.ErrNoResults if nothing survives.
+func (s *Service) search(ctx context.Context, searchers map[domain.Retailer]price.Searcher, params searchParams) ([]domain.Offer, error) {
+	offers, err := price.SearchPrices(ctx, params.query, searchers)
+	if err != nil {
+		return nil, err
+	}
+	if offers = params.apply(offers); len(offers) == 0 {
+		return nil, domain.ErrNoResults
+	}
+	return offers, nil
+}
+
+// changed reports whether the best offer of a watch moved to another listing
+// or another price.
+func changed(prev, cur domain.Offer) bool {
+	return prev.URL != cur.URL || prev.Price != cur.Price || prev.Shipping != cur.Shipping
+}
+
+// searchParams is a validated SearchRequest.
+type searchParams struct {
+	query        string
+	minRelevance float64
+	exclude      []string
+	refine       price.Refinement
+}
+
+// newSearchParams validates req for a search of retailers, returning an
+// InvalidArgument status if it is missing a query, names an unknown sort key,
+// condition or fulfillment, sorts by landed cost or deal score, or limits
+// prices across currencies.
+func newSearchParams(req *pricesearchpb.SearchRequest, retailers []domain.Retailer) (searchParams, error) {
+	p := searchParams{
+		query:        req.GetQuery(),
+		minRelevance: req.GetMinRelevance(),
+		exclude:      req.GetExclude(),
+		refine: price.Refinement{
+			MinPrice:  req.GetMinPrice(),
+			MaxPrice:  req.GetMaxPrice(),
+			InStock:   req.GetInStock(),
+			PrimeOnly: req.GetPrimeOnly(),
+			MinRating: req.GetMinRating(),
+			Desc:      req.GetDesc(),
+		},
+	}
+	if p.query == "" {
+		return p, status.Error(codes.InvalidArgument, "missing query")
+	}
+	var err error
+	if p.refine.Sort, err = price.ParseSortKey(req.GetSort()); err != nil {
+		return p, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if p.refine.Sort == price.SortLanded || p.refine.Sort == price.SortDeal {
+		// The service neither estimates tax nor rates deals.
+		return p, status.Errorf(codes.InvalidArgument, "sort %q is only available in the CLI", p.refine.Sort)
+	}
+	if p.refine.Condition, err = price.ParseCondition(req.GetCondition()); err != nil {
+		return p, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if p.refine.Fulfillment, err = price.ParseFulfillment(req.GetFulfillment()); err != nil {
+		return p, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = p.refine.Check(retailers); err != nil {
+		return p, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return p, nil
+}
+
+// apply scores offers for relevance and applies the filters and sort order.
+func (p searchParams) apply(offers []domain.Offer) []domain.Offer {
+	offers = relevance.Scorer{Exclude: p.exclude}.Score(p.query, offers)
+	offers = relevance.Filter(offers, p.minRelevance)
+	return p.refine.Apply(offers)
+}
+
+// statusError maps a domain error to a gRPC status.
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrNoResults), errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrInvalidRef):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrAuth):
+		// The server's own Zinc API key was rejected; the caller cannot fix
+		// that by sending different credentials.
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrNetwork):
+		code = codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,282 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/grpcapi/pricesearchpb"
+	"savvyshopper/internal/price"
+)
+
+type mockSearcher struct {
+	results []domain.Offer
+	err     error
+	latency time.Duration
+}
+
+func (m *mockSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	if m.latency > 0 {
+		time.Sleep(m.latency)
+	}
+	return m.results, m.err
+}
+
+// priceDropSearcher returns a lower price from its third call on.
+type priceDropSearcher struct {
+	calls atomic.Int32
+}
+
+func (p *priceDropSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	offer := domain.Offer{Title: "AirPods Pro 2", Price: 199.99, URL: "https://example.com/a"}
+	if p.calls.Add(1) >= 3 {
+		offer.Price = 179.99
+	}
+	return []domain.Offer{offer}, nil
+}
+
+type mockProducts struct {
+	details domain.ProductDetails
+	err     error
+}
+
+func (m mockProducts) Lookup(ctx context.Context, retailer domain.Retailer, productID string) (domain.ProductDetails, error) {
+	if m.err != nil {
+		return domain.ProductDetails{}, m.err
+	}
+	d := m.details
+	d.Retailer, d.ProductID = retailer, productID
+	return d, nil
+}
+
+func testSearchers() map[domain.Retailer]price.Searcher {
+	return map[domain.Retailer]price.Searcher{
+		domain.Amazon: &mockSearcher{results: []domain.Offer{
+			{Title: "AirPods Pro 2", Price: 199.99, URL: "https://example.com/a"},
+			{Title: "Case for AirPods Pro 2", Price: 9.99, URL: "https://example.com/case"},
+		}, latency: 30 * time.Millisecond},
+		domain.Walmart: &mockSearcher{results: []domain.Offer{
+			{Title: "AirPods Pro 2", Price: 189.00, URL: "https://example.com/w"},
+		}},
+	}
+}
+
+// dial serves svc over an in-memory listener and returns a client for it.
+func dial(t *testing.T, svc *Service) pricesearchpb.PriceSearchClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pricesearchpb.RegisterPriceSearchServer(srv, svc)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pricesearchpb.NewPriceSearchClient(conn)
+}
+
+func TestSearch(t *testing.T) {
+	client := dial(t, New(mockProducts{}, testSearchers()))
+
+	resp, err := client.Search(context.Background(), &pricesearchpb.SearchRequest{Query: "airpods pro 2", MinRelevance: 0.5, Sort: "price", Desc: true})
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	offers := resp.GetOffers()
+	if len(offers) != 2 {
+		t.Fatalf("expected accessory to be filtered, got %v", offers)
+	}
+	if offers[0].GetPrice() != 199.99 || offers[0].GetRetailer() != "Amazon" || offers[0].GetLanded() != 199.99 {
+		t.Errorf("expected descending price order, got %v", offers)
+	}
+}
+
+func TestSearch_Exclude(t *testing.T) {
+	client := dial(t, New(mockProducts{}, testSearchers()))
+
+	resp, err := client.Search(context.Background(), &pricesearchpb.SearchRequest{Query: "airpods pro 2", Exclude: []string{"case"}})
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	if offers := resp.GetOffers(); len(offers) != 2 {
+		t.Errorf("expected the excluded case to be dropped without min_relevance, got %v", offers)
+	}
+}
+
+func TestSearch_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchers map[domain.Retailer]price.Searcher
+		req       *pricesearchpb.SearchRequest
+		want      codes.Code
+	}{
+		{"missing query", testSearchers(), &pricesearchpb.SearchRequest{}, codes.InvalidArgument},
+		{"bad sort", testSearchers(), &pricesearchpb.SearchRequest{Query: "x", Sort: "cheapest"}, codes.InvalidArgument},
+		{"deal sort", testSearchers(), &pricesearchpb.SearchRequest{Query: "x", Sort: "deal"}, codes.InvalidArgument},
+		{"landed sort", testSearchers(), &pricesearchpb.SearchRequest{Query: "x", Sort: "landed"}, codes.InvalidArgument},
+		{"no results", testSearchers(), &pricesearchpb.SearchRequest{Query: "airpods", MinPrice: 1000}, codes.NotFound},
+		{"network", map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{err: domain.ErrNetwork}}, &pricesearchpb.SearchRequest{Query: "x"}, codes.Unavailable},
+		{"auth", map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{err: domain.ErrAuth}}, &pricesearchpb.SearchRequest{Query: "x"}, codes.FailedPrecondition},
+		{"price limit across currencies", map[domain.Retailer]price.Searcher{domain.Amazon: &mockSearcher{}, domain.AmazonJP: &mockSearcher{}}, &pricesearchpb.SearchRequest{Query: "x", MaxPrice: 200}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dial(t, New(mockProducts{}, tt.searchers))
+			_, err := client.Search(context.Background(), tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("Search() code = %v, want %v (err %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestStreamSearch(t *testing.T) {
+	client := dial(t, New(mockProducts{}, testSearchers()))
+
+	stream, err := client.StreamSearch(context.Background(), &pricesearchpb.SearchRequest{Query: "airpods pro 2"})
+	if err != nil {
+		t.Fatalf("StreamSearch error = %v", err)
+	}
+	var kinds []pricesearchpb.SearchEvent_Kind
+	var offerRetailers []string
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv error = %v", err)
+		}
+		kinds = append(kinds, ev.GetKind())
+		if ev.GetKind() == pricesearchpb.SearchEvent_KIND_OFFERS {
+			offerRetailers = append(offerRetailers, ev.GetRetailer())
+		}
+	}
+
+	if len(kinds) != 5 || kinds[len(kinds)-1] != pricesearchpb.SearchEvent_KIND_DONE {
+		t.Errorf("unexpected events %v", kinds)
+	}
+	if len(offerRetailers) != 2 || offerRetailers[0] != "Walmart" {
+		t.Errorf("expected faster Walmart first, got %v", offerRetailers)
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	products := mockProducts{details: domain.ProductDetails{
+		Title:    "Apple AirPods Pro 2",
+		Variants: []domain.Variant{{ProductID: "B0D1XD1ZV3", Specifics: map[string]string{"Color": "White"}}},
+		Offers:   []domain.Offer{{Seller: "Amazon.com", Price: 189.99}},
+	}}
+	client := dial(t, New(products, testSearchers()))
+
+	p, err := client.GetProduct(context.Background(), &pricesearchpb.GetProductRequest{Ref: "https://www.amazon.com/dp/B0CHWRXH8B"})
+	if err != nil {
+		t.Fatalf("GetProduct error = %v", err)
+	}
+	if p.GetRetailer() != "Amazon" || p.GetProductId() != "B0CHWRXH8B" || p.GetTitle() != "Apple AirPods Pro 2" {
+		t.Errorf("unexpected product %v", p)
+	}
+	if len(p.GetOffers()) != 1 || p.GetVariants()[0].GetSpecifics()["Color"] != "White" {
+		t.Errorf("unexpected offers or variants %v", p)
+	}
+
+	if _, err := client.GetProduct(context.Background(), &pricesearchpb.GetProductRequest{Ref: "not a product"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid ref code = %v, want InvalidArgument", status.Code(err))
+	}
+	client = dial(t, New(mockProducts{err: domain.ErrNotFound}, testSearchers()))
+	if _, err := client.GetProduct(context.Background(), &pricesearchpb.GetProductRequest{Ref: "B0CHWRXH8B"}); status.Code(err) != codes.NotFound {
+		t.Errorf("missing product code = %v, want NotFound", status.Code(err))
+	}
+}
+
+func TestWatch_SendsOnChange(t *testing.T) {
+	defer func(def, min time.Duration) { defaultWatchInterval, minWatchInterval = def, min }(defaultWatchInterval, minWatchInterval)
+	defaultWatchInterval, minWatchInterval = 10*time.Millisecond, 10*time.Millisecond
+
+	client := dial(t, New(mockProducts{}, map[domain.Retailer]price.Searcher{domain.Amazon: &priceDropSearcher{}}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &pricesearchpb.WatchRequest{Search: &pricesearchpb.SearchRequest{Query: "airpods pro 2"}})
+	if err != nil {
+		t.Fatalf("Watch error = %v", err)
+	}
+
+	var prices []float64
+	for len(prices) < 2 {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv error = %v", err)
+		}
+		prices = append(prices, update.GetBest().GetPrice())
+	}
+	// The unchanged second search must not produce an update.
+	if prices[0] != 199.99 || prices[1] != 179.99 {
+		t.Errorf("updates = %v, want [199.99 179.99]", prices)
+	}
+}
+
+func TestWatch_BypassesCache(t *testing.T) {
+	defer func(def, min time.Duration) { defaultWatchInterval, minWatchInterval = def, min }(defaultWatchInterval, minWatchInterval)
+	defaultWatchInterval, minWatchInterval = 10*time.Millisecond, 10*time.Millisecond
+
+	searcher := &priceDropSearcher{}
+	svc := New(mockProducts{}, map[domain.Retailer]price.Searcher{domain.Amazon: searcher})
+	svc.EnableCache(time.Hour)
+	client := dial(t, svc)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Search fills the cache, and serves the repeat from it.
+	for range 2 {
+		if _, err := client.Search(ctx, &pricesearchpb.SearchRequest{Query: "airpods pro 2"}); err != nil {
+			t.Fatalf("Search error = %v", err)
+		}
+	}
+	if n := searcher.calls.Load(); n != 1 {
+		t.Fatalf("searcher called %d times, want 1 with the cache", n)
+	}
+
+	stream, err := client.Watch(ctx, &pricesearchpb.WatchRequest{Search: &pricesearchpb.SearchRequest{Query: "airpods pro 2"}})
+	if err != nil {
+		t.Fatalf("Watch error = %v", err)
+	}
+	var prices []float64
+	for len(prices) < 2 {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv error = %v", err)
+		}
+		prices = append(prices, update.GetBest().GetPrice())
+	}
+	if prices[0] != 199.99 || prices[1] != 179.99 {
+		t.Errorf("updates = %v, want the price drop [199.99 179.99]", prices)
+	}
+}
+
+func TestWatch_InvalidRequest(t *testing.T) {
+	client := dial(t, New(mockProducts{}, testSearchers()))
+	stream, err := client.Watch(context.Background(), &pricesearchpb.WatchRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Watch() code = %v, want InvalidArgument", status.Code(err))
+	}
+}
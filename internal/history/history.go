@@ -0,0 +1,188 @@
+// Package history keeps the prices offers were seen at, so a price can be
+// judged against its recent past.
+//
+// The history is a JSON Lines file, one Point per line, that searches append
+// to. It only grows; delete it to start over.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/urlnorm"
+)
+
+// Point is the price an offer was seen at.
+type Point struct {
+	Time      time.Time       `json:"time"`
+	Key       string          `json:"key"`
+	Retailer  domain.Retailer `json:"retailer"`
+	Title     string          `json:"title"`
+	URL       string          `json:"url"`
+	Price     float64         `json:"price"`
+	ListPrice float64         `json:"list_price,omitempty"`
+}
+
+// Key identifies the listing behind o across searches: its marketplace and
+// product ID when known, and its URL cleaned of tracking otherwise. See
+// urlnorm.ProductKey.
+func Key(o domain.Offer) string {
+	return urlnorm.ProductKey(o)
+}
+
+// ProductKey returns the key of the listing productID on retailer's
+// marketplace, such as amazon:B0D1XD1ZV3.
+func ProductKey(retailer domain.Retailer, productID string) string {
+	return urlnorm.Ref{Retailer: retailer, ID: productID}.Key()
+}
+
+// DefaultPath returns savvyshopper/history.jsonl in the user config
+// directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate price history: %w", err)
+	}
+	return filepath.Join(dir, "savvyshopper", "history.jsonl"), nil
+}
+
+// Store is a price history backed by a file. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	points map[string][]Point
+}
+
+// Open reads the history at path. A missing file is an empty history; it is
+// created on the first Record.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, points: make(map[string][]Point)}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var p Point
+		if err := json.Unmarshal(sc.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("failed to parse price history %s line %d: %w", path, line, err)
+		}
+		s.points[p.Key] = append(s.points[p.Key], p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+	return s, nil
+}
+
+// Path returns the file backing s.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Points returns the prices recorded for key, oldest first.
+func (s *Store) Points(key string) []Point {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Point(nil), s.points[key]...)
+}
+
+// Between returns every point recorded from from up to but not including
+// to, oldest first. A zero from or to leaves that end open.
+func (s *Store) Between(from, to time.Time) []Point {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Point
+	for _, points := range s.points {
+		for _, p := range points {
+			if (from.IsZero() || !p.Time.Before(from)) && (to.IsZero() || p.Time.Before(to)) {
+				out = append(out, p)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].Time.Equal(out[j].Time) {
+			return out[i].Time.Before(out[j].Time)
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// Low returns the cheapest point recorded for key at or after since.
+func (s *Store) Low(key string, since time.Time) (Point, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var low Point
+	found := false
+	for _, p := range s.points[key] {
+		if p.Time.Before(since) {
+			continue
+		}
+		if !found || p.Price < low.Price {
+			low, found = p, true
+		}
+	}
+	return low, found
+}
+
+// Record appends the offers' prices, seen at time at, to the history. Offers
+// without a price are skipped.
+func (s *Store) Record(offers []domain.Offer, at time.Time) error {
+	var buf []byte
+	var added []Point
+	for _, o := range offers {
+		if o.Price <= 0 {
+			continue
+		}
+		p := Point{Time: at.UTC(), Key: Key(o), Retailer: o.Retailer, Title: o.Title, URL: o.URL, Price: o.Price, ListPrice: o.ListPrice}
+		line, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("failed to encode price history: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+		added = append(added, p)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write price history: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write price history: %w", err)
+	}
+	_, err = f.Write(buf)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write price history: %w", err)
+	}
+	for _, p := range added {
+		s.points[p.Key] = append(s.points[p.Key], p)
+	}
+	return nil
+}
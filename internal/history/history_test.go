@@ -0,0 +1,92 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+func TestStore_RecordAndLow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	offer := domain.Offer{Title: "AirPods", Price: 199, ProductID: "B0D1XD1ZV3", Retailer: domain.Amazon, URL: "https://www.amazon.com/dp/B0D1XD1ZV3"}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, p := range []struct {
+		daysAgo int
+		price   float64
+	}{{100, 149}, {60, 169}, {10, 189}, {0, 199}} {
+		offer.Price = p.price
+		if err := s.Record([]domain.Offer{offer, {Title: "unpriced"}}, now.AddDate(0, 0, -p.daysAgo)); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	// Reopen to read back what was written.
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	key := Key(offer)
+	if key != "amazon:B0D1XD1ZV3" {
+		t.Errorf("Key() = %q", key)
+	}
+	if got := len(s.Points(key)); got != 4 {
+		t.Errorf("Points() has %d points, want 4", got)
+	}
+	if low, ok := s.Low(key, now.AddDate(0, 0, -30)); !ok || low.Price != 189 {
+		t.Errorf("30-day Low() = %v, %v, want 189", low.Price, ok)
+	}
+	if low, ok := s.Low(key, now.AddDate(0, 0, -90)); !ok || low.Price != 169 {
+		t.Errorf("90-day Low() = %v, %v, want 169", low.Price, ok)
+	}
+	if _, ok := s.Low("walmart:1", now.AddDate(0, 0, -90)); ok {
+		t.Error("Low() found a price for an unseen listing")
+	}
+}
+
+func TestKey_FallsBackToURL(t *testing.T) {
+	o := domain.Offer{URL: "https://example.com/1", Retailer: domain.Walmart}
+	if got := Key(o); got != o.URL {
+		t.Errorf("Key() = %q, want the URL", got)
+	}
+}
+
+func TestOpen_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte("{\"key\":\"a\",\"price\":1}\n\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Open() error = %v, want one naming line 3", err)
+	}
+}
+
+func TestStore_Between(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	amazon := domain.Offer{Title: "AirPods", Price: 199, ProductID: "B0D1XD1ZV3", Retailer: domain.Amazon}
+	walmart := domain.Offer{Title: "AirPods", Price: 189, ProductID: "5689919121", Retailer: domain.Walmart}
+	for _, d := range []int{1, 2, 3} {
+		if err := s.Record([]domain.Offer{walmart, amazon}, day(d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := s.Between(day(2), day(3))
+	if len(got) != 2 || got[0].Key != "amazon:B0D1XD1ZV3" || got[1].Key != "walmart:5689919121" || !got[0].Time.Equal(day(2)) {
+		t.Errorf("Between(2, 3) = %+v", got)
+	}
+	if got := s.Between(time.Time{}, time.Time{}); len(got) != 6 || !got[0].Time.Equal(day(1)) || !got[5].Time.Equal(day(3)) {
+		t.Errorf("Between(open, open) = %+v", got)
+	}
+}
@@ -0,0 +1,242 @@
+// Package httpfixture records HTTP exchanges to fixture files and replays
+// them, so tests can exercise real Zinc payloads without the network.
+//
+// Tests replay by default. Setting SAVVY_RECORD=1 sends requests to the real
+// server instead and rewrites the fixture files. Secrets are scrubbed from
+// recorded requests before they are written.
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"savvyshopper/internal/logging"
+)
+
+// RecordEnv is the environment variable that switches to record mode.
+const RecordEnv = "SAVVY_RECORD"
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// Replay answers every request from the fixture file.
+	Replay Mode = iota
+	// Record sends requests to the real server and saves the exchanges.
+	Record
+)
+
+// ModeFromEnv returns Record if SAVVY_RECORD is set to 1 or true, and Replay
+// otherwise.
+func ModeFromEnv() Mode {
+	switch os.Getenv(RecordEnv) {
+	case "1", "true":
+		return Record
+	}
+	return Replay
+}
+
+// ErrNoMatch is returned in replay mode for a request the fixture does not
+// contain.
+var ErrNoMatch = errors.New("no recorded response for request")
+
+// Fixture is the on-disk form of a recording.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Header holds the scrubbed headers; the
+// body is kept as JSON when it is JSON, so fixtures stay readable.
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// Text holds a body that is not valid JSON, such as a proxy's HTML
+	// error page.
+	Text string `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records or replays exchanges.
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+}
+
+// New creates a Recorder for the fixture file at path. In Replay mode the
+// file must exist. In Record mode requests go through next, or
+// http.DefaultTransport if next is not given, and Save writes them to path.
+func New(path string, mode Mode, nextOpt ...http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, next: http.DefaultTransport}
+	if len(nextOpt) > 0 && nextOpt[0] != nil {
+		r.next = nextOpt[0]
+	}
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.fixture); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.fixture.Interactions))
+	}
+	return r, nil
+}
+
+// Client returns an HTTP client that sends requests through r.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := Request{
+		Method: req.Method,
+		URL:    logging.RedactURL(req.URL.String()),
+		Header: scrubHeader(req.Header),
+		Body:   rawJSON(body),
+	}
+
+	if r.mode == Replay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recordedResp := Response{Status: resp.StatusCode, Header: scrubHeader(resp.Header)}
+	if raw := rawJSON(respBody); raw != nil {
+		recordedResp.Body = raw
+	} else {
+		recordedResp.Text = string(respBody)
+	}
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{Request: recorded, Response: recordedResp})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction recorded for the same
+// method, URL and body. Repeated identical requests get their responses in
+// the order they were recorded.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.fixture.Interactions {
+		if r.used[i] || in.Request.Method != recorded.Method || in.Request.URL != recorded.URL || !sameJSON(in.Request.Body, recorded.Body) {
+			continue
+		}
+		r.used[i] = true
+		// Fixtures are indented for reading; serve the compact form.
+		body := []byte(rawJSON(in.Response.Body))
+		if in.Response.Text != "" {
+			body = []byte(in.Response.Text)
+		}
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s in %s", ErrNoMatch, recorded.Method, recorded.URL, r.path)
+}
+
+// Save writes the recorded exchanges to the fixture file. It does nothing in
+// Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// scrubHeader drops headers that vary between runs and redacts secrets.
+func scrubHeader(h http.Header) http.Header {
+	out := logging.RedactHeader(h)
+	for _, name := range []string{"Date", "Set-Cookie", "Traceparent", "Tracestate", "User-Agent", "Content-Length", "Accept-Encoding"} {
+		out.Del(name)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// rawJSON returns body as a RawMessage if it is JSON, and nil otherwise.
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 || !json.Valid(body) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, body); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// sameJSON reports whether two bodies hold equal JSON, ignoring formatting.
+func sameJSON(a, b json.RawMessage) bool {
+	return bytes.Equal(rawJSON(a), rawJSON(b))
+}
@@ -0,0 +1,86 @@
+package httpfixture
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		io.WriteString(w, `{"n": `+strings.Repeat("1", calls)+`}`)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatalf("New(Record) error = %v", err)
+	}
+	for range 2 {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/search?api_key=sk-secret", strings.NewReader(`{"q": "x"}`))
+		req.Header.Set("Authorization", "Bearer sk-secret")
+		resp, err := rec.Client().Do(req)
+		if err != nil {
+			t.Fatalf("record request error = %v", err)
+		}
+		resp.Body.Close()
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "sk-secret") || strings.Contains(string(data), "session=abc") {
+		t.Fatalf("secrets were recorded:\n%s", data)
+	}
+
+	rep, err := New(path, Replay)
+	if err != nil {
+		t.Fatalf("New(Replay) error = %v", err)
+	}
+	// Identical requests get their responses in recorded order, whatever
+	// key the replaying test happens to use.
+	for _, want := range []string{`{"n":1}`, `{"n":11}`} {
+		resp, err := rep.Client().Post(server.URL+"/search?api_key=other", "application/json", strings.NewReader(`{"q":"x"}`))
+		if err != nil {
+			t.Fatalf("replay request error = %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != want {
+			t.Errorf("replayed %d %s, want 200 %s", resp.StatusCode, body, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("replay reached the server: %d calls", calls)
+	}
+
+	_, err = rep.Client().Post(server.URL+"/search", "application/json", strings.NewReader(`{"q":"y"}`))
+	if !errors.Is(err, ErrNoMatch) {
+		t.Errorf("unrecorded request error = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(RecordEnv, "1")
+	if ModeFromEnv() != Record {
+		t.Errorf("SAVVY_RECORD=1 should record")
+	}
+	t.Setenv(RecordEnv, "")
+	if ModeFromEnv() != Replay {
+		t.Errorf("replay should be the default")
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay); err == nil {
+		t.Errorf("replaying a missing fixture should fail")
+	}
+}
@@ -0,0 +1,98 @@
+// Package launch hands offer URLs to the desktop: it opens them in a browser
+// with a configurable command, or copies them to the clipboard through the
+// terminal with an OSC 52 escape sequence, which also works over SSH.
+package launch
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Opener opens a URL, typically in a browser.
+type Opener interface {
+	Open(ctx context.Context, url string) error
+}
+
+// Command is an Opener that runs a program with the URL as its last
+// argument. It waits for the program to exit, so a browser given directly
+// should be one that hands the URL to a running instance and returns.
+type Command []string
+
+// DefaultCommand returns the command that opens URLs in the system's default
+// browser: open on macOS, the URL protocol handler on Windows and xdg-open
+// elsewhere.
+func DefaultCommand() Command {
+	switch runtime.GOOS {
+	case "darwin":
+		return Command{"open"}
+	case "windows":
+		return Command{"rundll32", "url.dll,FileProtocolHandler"}
+	}
+	return Command{"xdg-open"}
+}
+
+// ParseCommand splits cmdline, such as "firefox --new-tab", into a Command
+// at spaces. An empty cmdline gives DefaultCommand.
+func ParseCommand(cmdline string) Command {
+	if fields := strings.Fields(cmdline); len(fields) > 0 {
+		return fields
+	}
+	return DefaultCommand()
+}
+
+// Open runs the command with url appended.
+func (c Command) Open(ctx context.Context, url string) error {
+	if len(c) == 0 {
+		return errors.New("no command to open URLs with")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c[0], append(c[1:len(c):len(c)], url)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", c[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", c[0], err)
+	}
+	return nil
+}
+
+// Copy writes the OSC 52 escape sequence that asks the terminal to put text
+// on the clipboard. Inside tmux, which $TMUX reveals, the sequence is wrapped
+// so tmux passes it through to the terminal. Terminals that do not support
+// OSC 52 ignore it.
+func Copy(w io.Writer, text string) error {
+	seq := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		seq = "\033Ptmux;" + strings.ReplaceAll(seq, "\033", "\033\033") + "\033\\"
+	}
+	_, err := io.WriteString(w, seq)
+	return err
+}
+
+// Terminal opens the controlling terminal, /dev/tty or CONOUT$ on Windows,
+// for writing escape sequences such as Copy's without mixing them into the
+// program's output. Without one, such as under a service manager, it falls
+// back to stderr. Close the result when done.
+func Terminal() io.WriteCloser {
+	name := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		name = "CONOUT$"
+	}
+	if f, err := os.OpenFile(name, os.O_WRONLY, 0); err == nil {
+		return f
+	}
+	return nopCloser{os.Stderr}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
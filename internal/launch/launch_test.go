@@ -0,0 +1,61 @@
+package launch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	got := ParseCommand("  firefox   --new-tab ")
+	if len(got) != 2 || got[0] != "firefox" || got[1] != "--new-tab" {
+		t.Errorf("ParseCommand() = %q", got)
+	}
+	if got, want := ParseCommand(""), DefaultCommand(); len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("ParseCommand(\"\") = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_Open(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "opened")
+	cmd := Command{"sh", "-c", `printf %s "$1" > "$0"`, out}
+	const url = "https://www.amazon.com/dp/B0BDHWDR12"
+	if err := cmd.Open(context.Background(), url); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != url {
+		t.Errorf("command got %q, want %q", got, url)
+	}
+
+	err := Command{"sh", "-c", "echo no display >&2; exit 3"}.Open(context.Background(), url)
+	if err == nil || !strings.Contains(err.Error(), "no display") {
+		t.Errorf("Open() error = %v, want the command's stderr", err)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	t.Setenv("TMUX", "")
+	var buf bytes.Buffer
+	if err := Copy(&buf, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "\033]52;c;aGk=\a"; got != want {
+		t.Errorf("Copy() wrote %q, want %q", got, want)
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	buf.Reset()
+	if err := Copy(&buf, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "\033Ptmux;\033\033]52;c;aGk=\a\033\\"; got != want {
+		t.Errorf("Copy() in tmux wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+// Package logging builds the structured logger used across the CLI and
+// carries it through a context.Context. Secrets such as API keys and
+// Authorization headers are redacted before anything is written.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Formats accepted by New.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "REDACTED"
+
+// sensitive lists attribute keys, header names and URL query parameters whose
+// values are never logged. Matching is case-insensitive and ignores '-' and
+// '_'.
+var sensitive = map[string]bool{
+	"apikey":        true,
+	"zincapikey":    true,
+	"key":           true,
+	"authorization": true,
+	"token":         true,
+	"accesstoken":   true,
+	"password":      true,
+	"secret":        true,
+	"cookie":        true,
+}
+
+func isSensitive(name string) bool {
+	name = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	return sensitive[name]
+}
+
+// New creates a logger writing to w at level in the given format (text or
+// json).
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: want text or json", format)
+	}
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("invalid log level %q: want debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// redact is a slog.HandlerOptions.ReplaceAttr that hides secret values.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if isSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	switch v := a.Value.Any().(type) {
+	case http.Header:
+		return slog.Any(a.Key, RedactHeader(v))
+	case *url.URL:
+		return slog.String(a.Key, RedactURL(v.String()))
+	case string:
+		if strings.Contains(v, "://") {
+			return slog.String(a.Key, RedactURL(v))
+		}
+	}
+	return a
+}
+
+// RedactHeader returns a copy of h with secret headers redacted.
+func RedactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if isSensitive(name) || strings.EqualFold(name, "Proxy-Authorization") {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// RedactURL hides user info and secret query parameters in a URL. Strings
+// that do not parse as URLs are returned unchanged.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	if u.User != nil {
+		u.User = url.User(Redacted)
+	}
+	q := u.Query()
+	changed := false
+	for name := range q {
+		if isSensitive(name) {
+			q[name] = []string{Redacted}
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying logger.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or one that discards
+// everything if there is none, so library code can always log.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return discard
+}
+
+var discard = slog.New(slog.DiscardHandler)
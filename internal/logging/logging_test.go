@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelDebug, FormatJSON)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Debug("request",
+		"api_key", "sk-secret",
+		"url", "https://user:pw@api.zinc.io/v1/search?retailer=amazon&api_key=sk-secret",
+		"headers", http.Header{"Authorization": {"Basic c2stc2VjcmV0Og=="}, "Content-Type": {"application/json"}},
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "sk-secret") || strings.Contains(out, "c2stc2VjcmV0") || strings.Contains(out, "pw@") {
+		t.Fatalf("secret leaked into log: %s", out)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if entry["api_key"] != Redacted || !strings.Contains(entry["url"].(string), "retailer=amazon") {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if !strings.Contains(out, "application/json") {
+		t.Errorf("harmless header was redacted: %s", out)
+	}
+}
+
+func TestNew_LevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelWarn, FormatText)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown", "retailer", "Amazon")
+	if got := buf.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "level=WARN msg=shown retailer=Amazon") {
+		t.Errorf("unexpected text output %q", got)
+	}
+
+	if _, err := New(&buf, slog.LevelInfo, "xml"); err == nil {
+		t.Errorf("New should reject format xml")
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("ParseLevel should reject verbose")
+	}
+	if level, err := ParseLevel("DEBUG"); err != nil || level != slog.LevelDebug {
+		t.Errorf("ParseLevel(DEBUG) = %v, %v", level, err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	// Without a logger, logging is a no-op rather than a panic.
+	FromContext(context.Background()).Error("dropped")
+
+	var buf bytes.Buffer
+	logger, _ := New(&buf, slog.LevelInfo, FormatText)
+	FromContext(WithContext(context.Background(), logger)).Info("kept")
+	if !strings.Contains(buf.String(), "msg=kept") {
+		t.Errorf("logger not carried by context: %q", buf.String())
+	}
+}
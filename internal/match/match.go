@@ -0,0 +1,174 @@
+// Package match groups offers from different retailers that refer to the same
+// product.
+package match
+
+import (
+	"strings"
+	"unicode"
+
+	"savvyshopper/domain"
+)
+
+// Basis describes the evidence used to put offers in the same cluster.
+type Basis string
+
+const (
+	ByGTIN  Basis = "gtin"
+	ByModel Basis = "model"
+	ByTitle Basis = "title"
+)
+
+// Confidence scores for identifier matches. Title matches use the title
+// similarity itself.
+const (
+	gtinConfidence  = 1.0
+	modelConfidence = 0.9
+)
+
+// DefaultThreshold is the minimum title similarity for two offers to be
+// considered the same product when no identifiers are available.
+const DefaultThreshold = 0.6
+
+// Cluster is a group of offers believed to be the same product.
+type Cluster struct {
+	Title  string
+	Offers []domain.Offer
+	// Confidence is the weakest link that joined an offer to the cluster,
+	// from 0 to 1. A cluster with a single offer has confidence 1.
+	Confidence float64
+	Basis      Basis
+}
+
+// Best returns the cheapest offer in the cluster for retailer, if any. A
+// retailer sells on one marketplace, so the prices compared share a currency.
+func (c Cluster) Best(retailer domain.Retailer) (domain.Offer, bool) {
+	var best domain.Offer
+	found := false
+	for _, o := range c.Offers {
+		if o.Retailer != retailer {
+			continue
+		}
+		if !found || o.EffectivePrice() < best.EffectivePrice() {
+			best, found = o, true
+		}
+	}
+	return best, found
+}
+
+// Group clusters offers by product identity. GTINs are tried first, then
+// model numbers, then fuzzy title similarity against threshold (DefaultThreshold
+// when omitted). Accessories never match a non-accessory by title alone, nor by
+// a model number read from a title, as in "Case for XR-500".
+// Clusters are returned in the order their first offer appears.
+func Group(offers []domain.Offer, thresholdOpt ...float64) []Cluster {
+	threshold := DefaultThreshold
+	if len(thresholdOpt) > 0 {
+		threshold = thresholdOpt[0]
+	}
+
+	var clusters []Cluster
+	for _, offer := range offers {
+		idx, confidence, basis := -1, 0.0, Basis("")
+		for i := range clusters {
+			c, b := score(clusters[i], offer, threshold)
+			if c > confidence {
+				idx, confidence, basis = i, c, b
+			}
+		}
+		if idx < 0 {
+			clusters = append(clusters, Cluster{
+				Title:      offer.Title,
+				Offers:     []domain.Offer{offer},
+				Confidence: 1,
+			})
+			continue
+		}
+		cl := &clusters[idx]
+		cl.Offers = append(cl.Offers, offer)
+		if len(cl.Offers) == 2 || confidence < cl.Confidence {
+			cl.Confidence = confidence
+			cl.Basis = basis
+		}
+	}
+	return clusters
+}
+
+// score returns how confident we are that offer belongs to c, or 0 if it does
+// not.
+func score(c Cluster, offer domain.Offer, threshold float64) (float64, Basis) {
+	gtin := normalizeGTIN(offer.GTIN)
+	models := offerModels(offer)
+	accessory := IsAccessory(offer.Title)
+
+	best, basis := 0.0, Basis("")
+	for _, member := range c.Offers {
+		memberGTIN := normalizeGTIN(member.GTIN)
+		if gtin != "" && memberGTIN != "" {
+			if gtin != memberGTIN {
+				// Conflicting barcodes are proof these are different products.
+				return 0, ""
+			}
+			return gtinConfidence, ByGTIN
+		}
+		mixed := accessory != IsAccessory(member.Title)
+		fromTitle := offer.Model == "" || member.Model == ""
+		if shareAny(models, offerModels(member)) && !(mixed && fromTitle) {
+			best, basis = modelConfidence, ByModel
+			continue
+		}
+		if mixed || numbersConflict(offer.Title, member.Title) {
+			continue
+		}
+		if sim := Similarity(offer.Title, member.Title); sim >= threshold && sim > best {
+			best, basis = sim, ByTitle
+		}
+	}
+	return best, basis
+}
+
+func offerModels(o domain.Offer) []string {
+	if o.Model != "" {
+		return []string{normalizeModel(o.Model)}
+	}
+	return ModelNumbers(o.Title)
+}
+
+// numbersConflict reports whether both titles carry version-like numbers and
+// neither set contains the other, as in "AirPods 2" versus "AirPods 3".
+func numbersConflict(a, b string) bool {
+	na, nb := numbers(a), numbers(b)
+	if len(na) == 0 || len(nb) == 0 {
+		return false
+	}
+	return !subset(na, nb) && !subset(nb, na)
+}
+
+func numbers(title string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range Tokens(title) {
+		if strings.IndexFunc(t, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+func subset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func shareAny(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
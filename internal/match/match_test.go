@@ -0,0 +1,128 @@
+package match
+
+import (
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestGroup_TitleSimilarity(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro (2nd Generation)", Price: 199.99, Retailer: domain.Amazon},
+		{Title: "Apple AirPods Pro 2 with MagSafe", Price: 189.00, Retailer: domain.Walmart},
+		{Title: "Silicone Case for AirPods Pro 2nd Generation", Price: 9.99, Retailer: domain.Amazon},
+	}
+
+	clusters := Group(offers)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+	if len(clusters[0].Offers) != 2 {
+		t.Errorf("expected AirPods listings to be grouped, got %+v", clusters[0].Offers)
+	}
+	if clusters[0].Basis != ByTitle {
+		t.Errorf("expected title basis, got %q", clusters[0].Basis)
+	}
+	if clusters[0].Confidence < DefaultThreshold || clusters[0].Confidence >= 1 {
+		t.Errorf("unexpected confidence %v", clusters[0].Confidence)
+	}
+	if len(clusters[1].Offers) != 1 || clusters[1].Confidence != 1 {
+		t.Errorf("expected accessory in its own cluster, got %+v", clusters[1])
+	}
+}
+
+func TestGroup_Identifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		offers    []domain.Offer
+		clusters  int
+		wantBasis Basis
+	}{
+		{
+			name: "upc and ean forms match",
+			offers: []domain.Offer{
+				{Title: "Headphones", GTIN: "194253397168", Retailer: domain.Amazon},
+				{Title: "Wireless Earbuds", GTIN: "0194253397168", Retailer: domain.Walmart},
+			},
+			clusters:  1,
+			wantBasis: ByGTIN,
+		},
+		{
+			name: "conflicting gtins never match",
+			offers: []domain.Offer{
+				{Title: "Apple AirPods Pro", GTIN: "194253397168", Retailer: domain.Amazon},
+				{Title: "Apple AirPods Pro", GTIN: "194253397175", Retailer: domain.Walmart},
+			},
+			clusters: 2,
+		},
+		{
+			name: "model number in title",
+			offers: []domain.Offer{
+				{Title: "Sony WH-1000XM5 Wireless Headphones", Retailer: domain.Amazon},
+				{Title: "Noise Cancelling Over-Ear WH1000XM5/B", Model: "WH1000XM5", Retailer: domain.Walmart},
+			},
+			clusters:  1,
+			wantBasis: ByModel,
+		},
+		{
+			name: "accessory naming the model in its title",
+			offers: []domain.Offer{
+				{Title: "Acme XR-500 Action Camera", Retailer: domain.Amazon},
+				{Title: "Protective Case for XR-500", Retailer: domain.Walmart},
+			},
+			clusters: 2,
+		},
+		{
+			name: "different generations",
+			offers: []domain.Offer{
+				{Title: "Apple AirPods 2", Retailer: domain.Amazon},
+				{Title: "Apple AirPods 3", Retailer: domain.Walmart},
+			},
+			clusters: 2,
+		},
+		{
+			name: "capacity is not a model number",
+			offers: []domain.Offer{
+				{Title: "Flash Drive 128GB", Retailer: domain.Amazon},
+				{Title: "Phone Case Bundle 128GB Edition", Retailer: domain.Walmart},
+			},
+			clusters: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := Group(tt.offers)
+			if len(clusters) != tt.clusters {
+				t.Fatalf("expected %d clusters, got %d: %+v", tt.clusters, len(clusters), clusters)
+			}
+			if tt.wantBasis != "" && clusters[0].Basis != tt.wantBasis {
+				t.Errorf("expected basis %q, got %q", tt.wantBasis, clusters[0].Basis)
+			}
+		})
+	}
+}
+
+func TestCluster_Best(t *testing.T) {
+	c := Cluster{Offers: []domain.Offer{
+		{Price: 20, Retailer: domain.Amazon},
+		{Price: 15, Retailer: domain.Amazon},
+		{Price: 18, Retailer: domain.Walmart},
+	}}
+	best, ok := c.Best(domain.Amazon)
+	if !ok || best.Price != 15 {
+		t.Errorf("Best(Amazon) = %v, %v; want 15", best.Price, ok)
+	}
+	if _, ok := (Cluster{}).Best(domain.Walmart); ok {
+		t.Errorf("Best on empty cluster should report false")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("AirPods Pro 2nd Gen", "airpods pro 2"); got != 1 {
+		t.Errorf("Similarity of equivalent titles = %v, want 1", got)
+	}
+	if got := Similarity("AirPods", "Galaxy Buds"); got != 0 {
+		t.Errorf("Similarity of unrelated titles = %v, want 0", got)
+	}
+}
@@ -0,0 +1,129 @@
+package match
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// stopWords are dropped before comparing titles; they carry no identity.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "with": true, "in": true,
+	"of": true, "by": true, "new": true, "gen": true, "generation": true,
+}
+
+// AccessoryTerms mark listings that are add-ons for a product rather than the
+// product itself. A bare "case" is not enough: "AirPods Pro with MagSafe Case"
+// is the real thing, "Case for AirPods Pro" is not.
+var AccessoryTerms = []string{
+	"case for", "cover for", "skin for", "sleeve for", "strap for", "band for",
+	"cable for", "charger for", "adapter for", "stand for", "holder for",
+	"mount for", "compatible with", "replacement", "screen protector", "ear tips",
+}
+
+// ordinal matches "2nd", "3rd", "4th" and friends so they compare equal to "2".
+var ordinal = regexp.MustCompile(`^(\d+)(st|nd|rd|th)$`)
+
+// measurement matches quantities such as "128GB" or "40mm" that look like
+// model numbers but are shared by many unrelated products.
+var measurement = regexp.MustCompile(`^[0-9.]+(GB|TB|MB|MAH|W|V|HZ|MM|CM|IN|OZ|LB|LBS|PACK|PK|CT|K|P|X)$`)
+
+// Tokens splits s into lower-case words with punctuation, stop words and
+// ordinal suffixes removed.
+func Tokens(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if stopWords[f] {
+			continue
+		}
+		if m := ordinal.FindStringSubmatch(f); m != nil {
+			f = m[1]
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// Similarity returns the Dice coefficient of the token sets of a and b,
+// between 0 (nothing in common) and 1 (same words).
+func Similarity(a, b string) float64 {
+	ta, tb := tokenSet(a), tokenSet(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ta)+len(tb))
+}
+
+// IsAccessory reports whether title looks like an accessory listing, such as
+// "Silicone Case for AirPods Pro".
+func IsAccessory(title string) bool {
+	padded := " " + strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ") + " "
+	for _, term := range AccessoryTerms {
+		if strings.Contains(padded, " "+term+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelNumbers extracts candidate model numbers from a title: tokens of five or
+// more characters mixing letters and digits, e.g. "MQD83AM/A" or "WH-1000XM5".
+func ModelNumbers(title string) []string {
+	var models []string
+	for _, f := range strings.FieldsFunc(strings.ToUpper(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '/'
+	}) {
+		m := normalizeModel(f)
+		if len(m) < 5 || measurement.MatchString(m) {
+			continue
+		}
+		if strings.IndexFunc(m, unicode.IsLetter) >= 0 && strings.IndexFunc(m, unicode.IsDigit) >= 0 {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// normalizeModel drops separators so "WH-1000XM5" and "WH1000XM5" compare equal.
+func normalizeModel(m string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '/' || r == ' ' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, m)
+}
+
+// normalizeGTIN strips non-digits and left-pads to 14 digits so a 12-digit
+// UPC and its 13-digit EAN form compare equal.
+func normalizeGTIN(code string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, code)
+	if digits == "" || len(digits) > 14 {
+		return ""
+	}
+	return strings.Repeat("0", 14-len(digits)) + digits
+}
+
+func tokenSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range Tokens(s) {
+		set[t] = true
+	}
+	return set
+}
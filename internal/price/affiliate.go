@@ -0,0 +1,30 @@
+package price
+
+import (
+	"context"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/urlnorm"
+)
+
+// affiliateSearcher adds a referral tag to the URLs of the offers another
+// searcher finds.
+type affiliateSearcher struct {
+	next Searcher
+	norm urlnorm.Normalizer
+}
+
+// NewAffiliateSearcher wraps next so the URLs of the offers it finds on
+// retailer's marketplace carry the affiliate tag, as the tag parameter on
+// Amazon and wmlspartner on Walmart.
+func NewAffiliateSearcher(retailer domain.Retailer, next Searcher, tag string) Searcher {
+	return &affiliateSearcher{next: next, norm: urlnorm.Normalizer{Tags: map[domain.Retailer]string{retailer: tag}}}
+}
+
+func (s *affiliateSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	offers, err := s.next.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.norm.Offers(offers), nil
+}
@@ -0,0 +1,120 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+// Circuit breaker settings used by DefaultSearchers.
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerState is the state of a circuit breaker. Its value is what the
+// savvyshopper_circuit_breaker_state gauge reports.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every search through.
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen lets one trial search through after the cooldown.
+	BreakerHalfOpen
+	// BreakerOpen fails searches immediately.
+	BreakerOpen
+)
+
+// breakerSearcher stops calling a retailer that keeps failing with network
+// errors, so a Zinc outage costs one fast failure instead of a slow retry
+// loop on every search.
+type breakerSearcher struct {
+	retailer  domain.Retailer
+	next      Searcher
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewBreakerSearcher wraps next with a circuit breaker that opens after
+// threshold consecutive network failures and lets a trial search through
+// once cooldown has passed.
+func NewBreakerSearcher(retailer domain.Retailer, next Searcher, threshold int, cooldown time.Duration) Searcher {
+	b := &breakerSearcher{
+		retailer:  retailer,
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+	b.setState(BreakerClosed)
+	return b
+}
+
+// Search implements Searcher.
+func (b *breakerSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	b.mu.Lock()
+	switch {
+	case b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown:
+		b.setState(BreakerHalfOpen)
+	case b.state != BreakerClosed:
+		// Open, or half-open with a trial already in flight.
+		b.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s temporarily unavailable", domain.ErrNetwork, b.retailer)
+	}
+	b.mu.Unlock()
+
+	offers, err := b.next.Search(ctx, query)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case err != nil && canceled(ctx, err):
+		// The caller hung up or the search ran out of time, which says
+		// nothing about the retailer. A trial that never finished leaves the
+		// breaker open for the next search to try again.
+		if b.state == BreakerHalfOpen {
+			b.setState(BreakerOpen)
+		}
+	case err == nil || !errors.Is(err, domain.ErrNetwork):
+		// Only outages count; a bad API key or an empty result is not the
+		// retailer being down.
+		b.failures = 0
+		b.setState(BreakerClosed)
+	case b.state == BreakerHalfOpen:
+		b.open()
+	default:
+		if b.failures++; b.failures >= b.threshold {
+			b.open()
+		}
+	}
+	return offers, err
+}
+
+// canceled reports whether err comes from ctx being cancelled or running out
+// of time rather than from the retailer.
+func canceled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// open trips the breaker. b.mu must be held.
+func (b *breakerSearcher) open() {
+	b.failures = 0
+	b.openedAt = b.now()
+	b.setState(BreakerOpen)
+}
+
+// setState records the new state. b.mu must be held.
+func (b *breakerSearcher) setState(s BreakerState) {
+	b.state = s
+	breakerState.WithLabelValues(string(b.retailer)).Set(float64(s))
+}
@@ -0,0 +1,82 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+func TestBreakerSearcher(t *testing.T) {
+	next := &countingSearcher{mockSearcher: mockSearcher{err: domain.ErrNetwork}}
+	b := NewBreakerSearcher("BreakerTest", next, 2, time.Minute).(*breakerSearcher)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	for range 2 {
+		b.Search(context.Background(), "q")
+	}
+	if b.state != BreakerOpen {
+		t.Fatalf("state = %v after 2 failures, want open", b.state)
+	}
+	if _, err := b.Search(context.Background(), "q"); !errors.Is(err, domain.ErrNetwork) || next.calls != 2 {
+		t.Errorf("open breaker should fail fast, got err %v after %d calls", err, next.calls)
+	}
+
+	// After the cooldown a failing trial opens it again, a good one closes it.
+	now = now.Add(time.Minute)
+	b.Search(context.Background(), "q")
+	if b.state != BreakerOpen || next.calls != 3 {
+		t.Errorf("failed trial: state = %v, calls = %d", b.state, next.calls)
+	}
+	now = now.Add(time.Minute)
+	next.err = nil
+	if _, err := b.Search(context.Background(), "q"); err != nil || b.state != BreakerClosed {
+		t.Errorf("successful trial: err = %v, state = %v", err, b.state)
+	}
+
+	// Errors other than outages do not count.
+	next.err = domain.ErrAuth
+	for range 3 {
+		b.Search(context.Background(), "q")
+	}
+	if b.state != BreakerClosed {
+		t.Errorf("auth failures opened the breaker")
+	}
+}
+
+func TestBreakerSearcher_Canceled(t *testing.T) {
+	next := &countingSearcher{mockSearcher: mockSearcher{err: domain.ErrNetwork}}
+	b := NewBreakerSearcher("BreakerCancelTest", next, 2, time.Minute).(*breakerSearcher)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	// Searches the caller gave up on, or that ran out of time, do not count.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	timedOut, stop := context.WithTimeout(context.Background(), 0)
+	defer stop()
+	for range 2 {
+		b.Search(ctx, "q")
+		b.Search(timedOut, "q")
+	}
+	if b.state != BreakerClosed {
+		t.Fatalf("state = %v after cancelled searches, want closed", b.state)
+	}
+
+	// A cancelled trial leaves the breaker open, ready for another trial.
+	for range 2 {
+		b.Search(context.Background(), "q")
+	}
+	now = now.Add(time.Minute)
+	b.Search(ctx, "q")
+	if b.state != BreakerOpen {
+		t.Fatalf("state = %v after a cancelled trial, want open", b.state)
+	}
+	next.err = nil
+	if _, err := b.Search(context.Background(), "q"); err != nil || b.state != BreakerClosed {
+		t.Errorf("next trial: err = %v, state = %v", err, b.state)
+	}
+}
@@ -0,0 +1,82 @@
+package price
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"savvyshopper/domain"
+)
+
+// cachedSearcher remembers successful results per query for a while, so
+// repeated searches in server mode do not each cost a Zinc request.
+type cachedSearcher struct {
+	retailer domain.Retailer
+	next     Searcher
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	offers  []domain.Offer
+	expires time.Time
+}
+
+// NewCachedSearcher wraps next so that results for a query are reused for
+// ttl. Failed searches are not cached.
+func NewCachedSearcher(retailer domain.Retailer, next Searcher, ttl time.Duration) Searcher {
+	return &cachedSearcher{
+		retailer: retailer,
+		next:     next,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+	}
+}
+
+// NewCachedSearchers wraps each of searchers with NewCachedSearcher.
+func NewCachedSearchers(searchers map[domain.Retailer]Searcher, ttl time.Duration) map[domain.Retailer]Searcher {
+	cached := make(map[domain.Retailer]Searcher, len(searchers))
+	for retailer, s := range searchers {
+		cached[retailer] = NewCachedSearcher(retailer, s, ttl)
+	}
+	return cached
+}
+
+// Search implements Searcher.
+func (c *cachedSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	_, span := tracer.Start(ctx, "cache.lookup", trace.WithAttributes(attribute.String("retailer", string(c.retailer))))
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.now().After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+	span.End()
+	if ok {
+		cacheRequestsTotal.WithLabelValues(string(c.retailer), "hit").Inc()
+		// Callers may modify the offers they get back.
+		return append([]domain.Offer(nil), entry.offers...), nil
+	}
+	cacheRequestsTotal.WithLabelValues(string(c.retailer), "miss").Inc()
+
+	offers, err := c.next.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{offers: append([]domain.Offer(nil), offers...), expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return offers, nil
+}
@@ -0,0 +1,54 @@
+package price
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+// countingSearcher records how many searches reached it.
+type countingSearcher struct {
+	mockSearcher
+	calls int
+}
+
+func (c *countingSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	c.calls++
+	return c.mockSearcher.Search(ctx, query)
+}
+
+func TestCachedSearcher(t *testing.T) {
+	next := &countingSearcher{mockSearcher: mockSearcher{results: []domain.Offer{{Title: "A", Price: 1}}}}
+	s := NewCachedSearcher("CacheTest", next, time.Minute).(*cachedSearcher)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	for _, q := range []string{"AirPods  Pro", "airpods pro"} {
+		offers, err := s.Search(context.Background(), q)
+		if err != nil || len(offers) != 1 {
+			t.Fatalf("Search(%q) = %v, %v", q, offers, err)
+		}
+		offers[0].Title = "modified"
+	}
+	if next.calls != 1 {
+		t.Errorf("expected the second search to be served from cache, got %d calls", next.calls)
+	}
+	if offers, _ := s.Search(context.Background(), "airpods pro"); offers[0].Title != "A" {
+		t.Errorf("cached offers were modified by a caller: %v", offers)
+	}
+
+	now = now.Add(2 * time.Minute)
+	s.Search(context.Background(), "airpods pro")
+	if next.calls != 2 {
+		t.Errorf("expected an expired entry to be refreshed, got %d calls", next.calls)
+	}
+
+	next.err = domain.ErrNetwork
+	s.Search(context.Background(), "other")
+	s.Search(context.Background(), "other")
+	if next.calls != 4 {
+		t.Errorf("failed searches should not be cached, got %d calls", next.calls)
+	}
+}
@@ -5,37 +5,112 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
 	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/logging"
+	"savvyshopper/internal/urlnorm"
 )
 
 // zincPayload represents the JSON payload for Zinc API requests.
 type zincPayload struct {
 	SearchTerm string `json:"search_term"`
 	Retailer   string `json:"retailer"`
-	MaxResults int    `json:"max_results"`
+	// Marketplace is the site's domain, sent only for sites outside the US.
+	Marketplace string `json:"marketplace,omitempty"`
+	MaxResults  int    `json:"max_results"`
 }
 
 // buildPayload creates a Zinc API payload for the given search term and retailer.
 func buildPayload(query string, retailer domain.Retailer) ([]byte, error) {
+	m := retailer.Marketplace()
 	payload := zincPayload{
 		SearchTerm: query,
-		Retailer:   string(retailer),
+		Retailer:   string(m.Brand),
 		MaxResults: 3, // We only need top 3 results per retailer
 	}
+	if m.Retailer != m.Brand {
+		payload.Marketplace = m.Domain
+	}
 	return json.Marshal(payload)
 }
 
 // zincResponse represents the JSON response from Zinc API.
 type zincResponse struct {
-	Results []struct {
-		Title string  `json:"title"`
-		Price float64 `json:"price"`
-		URL   string  `json:"url"`
-	} `json:"results"`
+	Results []zincResult `json:"results"`
+}
+
+// zincResult is a single search result in a zincResponse.
+type zincResult struct {
+	ProductID  string  `json:"product_id,omitempty"`
+	Title      string  `json:"title"`
+	Price      float64 `json:"price"`
+	URL        string  `json:"url"`
+	UPC        string  `json:"upc,omitempty"`
+	GTIN       string  `json:"gtin,omitempty"`
+	Model      string  `json:"model,omitempty"`
+	Shipping   float64 `json:"shipping,omitempty"`
+	Stars      float64 `json:"stars,omitempty"`
+	NumReviews int     `json:"num_reviews,omitempty"`
+	Condition  string  `json:"condition,omitempty"`
+	Prime      bool    `json:"prime,omitempty"`
+	// Available is omitted when the retailer does not report stock.
+	Available *bool `json:"available,omitempty"`
+	// ListPrice is the pre-sale reference price, when on sale.
+	ListPrice  float64         `json:"list_price,omitempty"`
+	Promotions []zincPromotion `json:"promotions,omitempty"`
+}
+
+// zincPromotion is a coupon, subscribe-and-save discount or member price
+// attached to a search result.
+type zincPromotion struct {
+	// Type is coupon, subscribe_save or member_price.
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	AmountOff   float64 `json:"amount_off,omitempty"`
+	PercentOff  float64 `json:"percent_off,omitempty"`
+	// Price is the member price of a member_price promotion.
+	Price float64 `json:"price,omitempty"`
+	// Membership is the program a member_price is for: prime or
+	// walmart_plus.
+	Membership string `json:"membership,omitempty"`
+}
+
+// promotions converts a result's promotions, skipping unknown types and
+// member prices that are no cheaper than the regular price.
+func promotions(result zincResult) []domain.Promotion {
+	var out []domain.Promotion
+	for _, p := range result.Promotions {
+		promo := domain.Promotion{Description: p.Description, AmountOff: p.AmountOff, PercentOff: p.PercentOff}
+		switch p.Type {
+		case "coupon":
+			promo.Kind = domain.PromotionCoupon
+		case "subscribe_save":
+			promo.Kind = domain.PromotionSubscribeSave
+		case "member_price":
+			if p.Price <= 0 || p.Price >= result.Price {
+				continue
+			}
+			promo.Kind = domain.PromotionMember
+			promo.Membership = domain.Membership(strings.ToLower(p.Membership))
+			promo.AmountOff, promo.PercentOff = result.Price-p.Price, 0
+		default:
+			continue
+		}
+		out = append(out, promo)
+	}
+	return out
 }
 
 // retryWithBackoff retries the given function with exponential back-off.
@@ -57,59 +132,143 @@ func retryWithBackoff(ctx context.Context, maxRetries int, baseDelay time.Durati
 	return err
 }
 
-// makeRequest sends a POST request to the Zinc API and returns the parsed response.
-func makeRequest(ctx context.Context, endpoint string, payload []byte, retailer domain.Retailer) ([]domain.Offer, error) {
+// doRequest sends a request to the Zinc API with retry and decodes the JSON
+// response body into out. payload may be nil for requests without a body.
+func doRequest(ctx context.Context, client *http.Client, method, endpoint string, payload []byte, out any) error {
 	// Create a new HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to create request: %v", domain.ErrNetwork, err)
+		return fmt.Errorf("%w: failed to create request: %v", domain.ErrNetwork, err)
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	// TODO: Add API key from config.APIKey()
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Zinc takes the API key as the Basic Auth username. Without one the
+	// request goes out bare and Zinc answers 401.
+	if key, err := config.APIKey(); err == nil {
+		req.SetBasicAuth(key, "")
 	}
 
-	// Send request with retry
+	// Send request with retry; each attempt gets a fresh copy of the body
+	logger := logging.FromContext(ctx)
 	var resp *http.Response
+	attempts := 0
 	err = retryWithBackoff(ctx, 3, 100*time.Millisecond, func() error {
+		if attempts++; attempts > 1 {
+			zincRetriesTotal.Inc()
+		}
+		ctx, span := tracer.Start(ctx, "HTTP "+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.full", endpoint),
+			attribute.Int("http.request.resend_count", attempts-1),
+			attribute.Int("retry.attempt", attempts),
+		))
+		defer span.End()
+		attempt := req.Clone(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(attempt.Header))
+		if payload != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(payload))
+			attempt.ContentLength = int64(len(payload))
+		}
+		began := time.Now()
 		var err error
-		resp, err = client.Do(req)
-		return err
+		resp, err = client.Do(attempt)
+		if err != nil {
+			logger.Warn("zinc request failed", "method", method, "url", endpoint, "attempt", attempts,
+				"duration", time.Since(began), "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		logger.Debug("zinc request", "method", method, "url", endpoint, "attempt", attempts,
+			"status", resp.StatusCode, "duration", time.Since(began))
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to send request: %v", domain.ErrNetwork, err)
+		return fmt.Errorf("%w: failed to send request: %v", domain.ErrNetwork, err)
 	}
 	defer resp.Body.Close()
 
 	// Check status code
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("%w: unauthorized request", domain.ErrAuth)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected status code: %d", domain.ErrNetwork, resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%w: unauthorized request", domain.ErrAuth)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, endpoint)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w: unexpected status code: %d", domain.ErrNetwork, resp.StatusCode)
 	}
 
 	// Parse response
+	_, span := tracer.Start(ctx, "zinc.decode")
+	body := &countingReader{r: resp.Body}
+	err = json.NewDecoder(body).Decode(out)
+	span.SetAttributes(attribute.Int64("bytes", body.n))
+	endSpan(span, err)
+	logger.Debug("zinc response", "url", endpoint, "bytes", body.n, "error", err)
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse response: %v", domain.ErrNetwork, err)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// makeRequest sends a POST request to the Zinc API and returns the parsed
+// response, with the offers' URLs normalized.
+func makeRequest(ctx context.Context, client *http.Client, endpoint string, payload []byte, retailer domain.Retailer) ([]domain.Offer, error) {
 	var zincResp zincResponse
-	if err := json.NewDecoder(resp.Body).Decode(&zincResp); err != nil {
-		return nil, fmt.Errorf("%w: failed to parse response: %v", domain.ErrNetwork, err)
+	if err := doRequest(ctx, client, http.MethodPost, endpoint, payload, &zincResp); err != nil {
+		return nil, err
 	}
 
 	// Convert to domain.Offer slice
 	offers := make([]domain.Offer, len(zincResp.Results))
 	for i, result := range zincResp.Results {
 		offers[i] = domain.Offer{
-			Title:    result.Title,
-			Price:    result.Price,
-			URL:      result.URL,
-			Retailer: retailer,
+			Title:      result.Title,
+			Price:      result.Price,
+			URL:        result.URL,
+			Retailer:   retailer,
+			ListPrice:  result.ListPrice,
+			Promotions: promotions(result),
+			ProductID:  result.ProductID,
+			GTIN:       firstNonEmpty(result.GTIN, result.UPC),
+			Model:      result.Model,
+			Shipping:   result.Shipping,
+			Rating:     result.Stars,
+			Reviews:    result.NumReviews,
+			Condition:  domain.Condition(strings.ToLower(result.Condition)),
+			Prime:      result.Prime,
+			OutOfStock: result.Available != nil && !*result.Available,
 		}
 	}
 
-	return offers, nil
+	return urlnorm.Normalizer{}.Offers(offers), nil
+}
+
+// firstNonEmpty returns the first non-empty string in values.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
 }
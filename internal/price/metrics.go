@@ -0,0 +1,99 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"savvyshopper/domain"
+)
+
+// Registry holds the package's Prometheus metrics. Server mode serves it on
+// /metrics and the CLI can dump it with WriteMetrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	searchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "savvyshopper_searches_total",
+		Help: "Searches across all retailers, by outcome.",
+	}, []string{"outcome"})
+	searchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "savvyshopper_search_duration_seconds",
+		Help:    "Time until every retailer answered or the search gave up.",
+		Buckets: prometheus.DefBuckets,
+	})
+	retailerRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "savvyshopper_retailer_requests_total",
+		Help: "Searches of a single retailer, by outcome.",
+	}, []string{"retailer", "outcome"})
+	retailerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "savvyshopper_retailer_request_duration_seconds",
+		Help:    "Time taken by a single retailer's searcher.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"retailer"})
+	zincRetriesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "savvyshopper_zinc_retries_total",
+		Help: "Zinc API requests sent again after a failed attempt.",
+	})
+	cacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "savvyshopper_cache_requests_total",
+		Help: "Search cache lookups, by retailer and result (hit or miss).",
+	}, []string{"retailer", "result"})
+	breakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "savvyshopper_circuit_breaker_state",
+		Help: "Circuit breaker state per retailer: 0 closed, 1 half-open, 2 open.",
+	}, []string{"retailer"})
+)
+
+func init() {
+	Registry.MustRegister(
+		searchesTotal,
+		searchDuration,
+		retailerRequestsTotal,
+		retailerRequestDuration,
+		zincRetriesTotal,
+		cacheRequestsTotal,
+		breakerState,
+	)
+}
+
+// WriteMetrics writes the current value of every metric in Registry to w in
+// the Prometheus text format.
+func WriteMetrics(w io.Writer) error {
+	families, err := Registry.Gather()
+	if err != nil {
+		return err
+	}
+	enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outcome names the result of a search for metric labels.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, domain.ErrNoResults):
+		return "no_results"
+	case errors.Is(err, domain.ErrAuth):
+		return "auth_error"
+	case errors.Is(err, domain.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, domain.ErrNetwork):
+		return "network_error"
+	default:
+		return "error"
+	}
+}
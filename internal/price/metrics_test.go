@@ -0,0 +1,59 @@
+package price
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"savvyshopper/domain"
+)
+
+func TestMetrics_StreamPrices(t *testing.T) {
+	ok, failing := domain.Retailer("MetricsOK"), domain.Retailer("MetricsFailing")
+	searchers := map[domain.Retailer]Searcher{
+		ok:      &mockSearcher{results: []domain.Offer{{Title: "A", Price: 1}}},
+		failing: NewBreakerSearcher(failing, &mockSearcher{err: domain.ErrNetwork}, 1, defaultBreakerCooldown),
+	}
+	before := testutil.ToFloat64(searchesTotal.WithLabelValues("ok"))
+
+	for range StreamPrices(context.Background(), "test", searchers) {
+	}
+
+	if got := testutil.ToFloat64(searchesTotal.WithLabelValues("ok")) - before; got != 1 {
+		t.Errorf("searches_total{outcome=ok} increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(retailerRequestsTotal.WithLabelValues(string(ok), "ok")); got != 1 {
+		t.Errorf("retailer_requests_total{ok} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(retailerRequestsTotal.WithLabelValues(string(failing), "network_error")); got != 1 {
+		t.Errorf("retailer_requests_total{failing} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(breakerState.WithLabelValues(string(failing))); got != float64(BreakerOpen) {
+		t.Errorf("circuit_breaker_state = %v, want open", got)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	cached := NewCachedSearcher("MetricsCache", &mockSearcher{results: []domain.Offer{{Title: "A"}}}, time.Minute)
+	cached.Search(context.Background(), "q")
+	cached.Search(context.Background(), "q")
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`savvyshopper_cache_requests_total{result="hit",retailer="MetricsCache"} 1`,
+		`savvyshopper_cache_requests_total{result="miss",retailer="MetricsCache"} 1`,
+		"# TYPE savvyshopper_zinc_retries_total counter",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
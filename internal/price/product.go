@@ -0,0 +1,198 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/urlnorm"
+)
+
+// DefaultZincBaseURL is the root of the Zinc API.
+const DefaultZincBaseURL = config.DefaultZincBaseURL
+
+// zincProduct represents the JSON response from Zinc's product details endpoint.
+type zincProduct struct {
+	ProductID   string   `json:"product_id"`
+	Title       string   `json:"title"`
+	Brand       string   `json:"brand,omitempty"`
+	Description string   `json:"product_description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Price       float64  `json:"price"`
+	Stars       float64  `json:"stars,omitempty"`
+	ReviewCount int      `json:"review_count,omitempty"`
+	UPC         string   `json:"upc,omitempty"`
+	GTIN        string   `json:"gtin,omitempty"`
+	Model       string   `json:"model,omitempty"`
+	Available   *bool    `json:"available,omitempty"`
+	Images      []string `json:"images,omitempty"`
+	AllVariants []struct {
+		ProductID        string `json:"product_id"`
+		VariantSpecifics []struct {
+			Dimension string `json:"dimension"`
+			Value     string `json:"value"`
+		} `json:"variant_specifics"`
+	} `json:"all_variants,omitempty"`
+}
+
+// zincOffersResponse represents the JSON response from Zinc's offers endpoint.
+type zincOffersResponse struct {
+	Offers []zincOffer `json:"offers"`
+}
+
+// zincOffer is a single seller's offer in a zincOffersResponse.
+type zincOffer struct {
+	Price     float64 `json:"price"`
+	Condition string  `json:"condition,omitempty"`
+	Prime     bool    `json:"prime,omitempty"`
+	Available *bool   `json:"available,omitempty"`
+	Seller    struct {
+		Name            string  `json:"name"`
+		NumRatings      int     `json:"num_ratings,omitempty"`
+		PercentPositive float64 `json:"percent_positive,omitempty"`
+		FirstParty      bool    `json:"first_party,omitempty"`
+	} `json:"seller"`
+	// FulfilledBy names who ships the item: the retailer code (e.g.
+	// "amazon") or "merchant".
+	FulfilledBy     string `json:"fulfilled_by,omitempty"`
+	ShippingOptions []struct {
+		Price float64 `json:"price"`
+	} `json:"shipping_options,omitempty"`
+}
+
+// ProductClient looks up a single listing through Zinc's product details and
+// offers endpoints.
+type ProductClient struct {
+	// Tags maps a retailer to the affiliate tag added to its listing URLs.
+	Tags map[domain.Retailer]string
+
+	baseURL string
+	client  *http.Client
+}
+
+// NewProductClient creates a ProductClient for the Zinc API rooted at baseURL,
+// e.g. DefaultZincBaseURL.
+// If clientOpt is provided, requests are sent with that client.
+func NewProductClient(baseURL string, clientOpt ...*http.Client) *ProductClient {
+	return &ProductClient{baseURL: strings.TrimRight(baseURL, "/"), client: clientFrom(clientOpt)}
+}
+
+// Lookup fetches a listing's details together with every seller's offer.
+func (c *ProductClient) Lookup(ctx context.Context, retailer domain.Retailer, productID string) (domain.ProductDetails, error) {
+	details, err := c.Details(ctx, retailer, productID)
+	if err != nil {
+		return domain.ProductDetails{}, err
+	}
+	offers, err := c.Offers(ctx, retailer, productID)
+	if err != nil {
+		return domain.ProductDetails{}, err
+	}
+	for i := range offers {
+		offers[i].Title = details.Title
+		offers[i].URL = details.URL
+	}
+	details.Offers = offers
+	return details, nil
+}
+
+// Details fetches a listing's description, images, variants and availability.
+func (c *ProductClient) Details(ctx context.Context, retailer domain.Retailer, productID string) (domain.ProductDetails, error) {
+	var p zincProduct
+	if err := doRequest(ctx, c.client, http.MethodGet, c.endpoint(retailer, productID, ""), nil, &p); err != nil {
+		return domain.ProductDetails{}, err
+	}
+
+	details := domain.ProductDetails{
+		Retailer:    retailer,
+		ProductID:   firstNonEmpty(p.ProductID, productID),
+		Title:       p.Title,
+		Brand:       p.Brand,
+		Description: p.Description,
+		URL:         p.URL,
+		Price:       p.Price,
+		Rating:      p.Stars,
+		Reviews:     p.ReviewCount,
+		GTIN:        firstNonEmpty(p.GTIN, p.UPC),
+		Model:       p.Model,
+		OutOfStock:  p.Available != nil && !*p.Available,
+		Images:      p.Images,
+	}
+	for _, v := range p.AllVariants {
+		variant := domain.Variant{ProductID: v.ProductID, Specifics: make(map[string]string)}
+		for _, s := range v.VariantSpecifics {
+			variant.Specifics[s.Dimension] = s.Value
+		}
+		details.Variants = append(details.Variants, variant)
+	}
+	details.URL = c.normalizer().Offer(details.Offer()).URL
+	return details, nil
+}
+
+// Offers fetches every seller's offer for a listing.
+func (c *ProductClient) Offers(ctx context.Context, retailer domain.Retailer, productID string) ([]domain.Offer, error) {
+	var resp zincOffersResponse
+	if err := doRequest(ctx, c.client, http.MethodGet, c.endpoint(retailer, productID, "offers"), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	offers := make([]domain.Offer, len(resp.Offers))
+	for i, o := range resp.Offers {
+		offers[i] = domain.Offer{
+			Price:         o.Price,
+			Retailer:      retailer,
+			ProductID:     productID,
+			Seller:        o.Seller.Name,
+			SellerRating:  o.Seller.PercentPositive,
+			SellerRatings: o.Seller.NumRatings,
+			Fulfillment:   fulfillment(retailer, o),
+			Shipping:      cheapestShipping(o),
+			Condition:     domain.Condition(strings.ToLower(o.Condition)),
+			Prime:         o.Prime,
+			OutOfStock:    o.Available != nil && !*o.Available,
+		}
+	}
+	return c.normalizer().Offers(offers), nil
+}
+
+func (c *ProductClient) normalizer() urlnorm.Normalizer {
+	return urlnorm.Normalizer{Tags: c.Tags}
+}
+
+// endpoint builds {base}/products/{id}[/{suffix}]?retailer={code}.
+func (c *ProductClient) endpoint(retailer domain.Retailer, productID, suffix string) string {
+	u := c.baseURL + "/products/" + url.PathEscape(productID)
+	if suffix != "" {
+		u += "/" + suffix
+	}
+	return u + "?retailer=" + url.QueryEscape(retailer.Marketplace().Zinc)
+}
+
+// fulfillment works out who ships an offer. First-party offers are always
+// shipped by the retailer.
+func fulfillment(retailer domain.Retailer, o zincOffer) domain.Fulfillment {
+	m := retailer.Marketplace()
+	switch {
+	case o.Seller.FirstParty || strings.EqualFold(o.FulfilledBy, string(m.Brand)) || strings.EqualFold(o.FulfilledBy, m.Zinc):
+		return domain.FulfilledByRetailer
+	case o.FulfilledBy != "":
+		return domain.FulfilledBySeller
+	}
+	return ""
+}
+
+// cheapestShipping returns the lowest shipping charge offered, or zero.
+func cheapestShipping(o zincOffer) float64 {
+	if len(o.ShippingOptions) == 0 {
+		return 0
+	}
+	cheapest := o.ShippingOptions[0].Price
+	for _, s := range o.ShippingOptions[1:] {
+		if s.Price < cheapest {
+			cheapest = s.Price
+		}
+	}
+	return cheapest
+}
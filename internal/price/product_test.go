@@ -0,0 +1,97 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestProductClient_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify request method and retailer
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET request, got %s", r.Method)
+		}
+		if r.URL.Query().Get("retailer") != "amazon" {
+			t.Errorf("expected retailer 'amazon', got %s", r.URL.Query().Get("retailer"))
+		}
+
+		switch r.URL.Path {
+		case "/products/B0BDHWDR12":
+			w.Write([]byte(`{
+				"product_id": "B0BDHWDR12",
+				"title": "Apple AirPods Pro (2nd Generation)",
+				"product_description": "Active Noise Cancellation",
+				"url": "https://www.amazon.com/dp/B0BDHWDR12",
+				"price": 199.99,
+				"upc": "194253397168",
+				"images": ["a.jpg", "b.jpg", "c.jpg"],
+				"all_variants": [
+					{"product_id": "B0BDHWDR12", "variant_specifics": [{"dimension": "Style", "value": "Lightning"}]},
+					{"product_id": "B0CHWRXH8B", "variant_specifics": [{"dimension": "Style", "value": "USB-C"}]}
+				]
+			}`))
+		case "/products/B0BDHWDR12/offers":
+			json.NewEncoder(w).Encode(map[string]any{
+				"offers": []map[string]any{
+					{"price": 199.99, "condition": "New", "prime": true, "seller": map[string]any{"name": "Amazon.com", "first_party": true}},
+					{"price": 174.50, "condition": "Used", "fulfilled_by": "merchant",
+						"seller":           map[string]any{"name": "Gadget Outlet", "num_ratings": 1520, "percent_positive": 97},
+						"shipping_options": []map[string]any{{"price": 5.99}, {"price": 3.99}}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	details, err := NewProductClient(server.URL).Lookup(context.Background(), domain.Amazon, "B0BDHWDR12")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	if details.Title != "Apple AirPods Pro (2nd Generation)" {
+		t.Errorf("unexpected title %q", details.Title)
+	}
+	if details.GTIN != "194253397168" {
+		t.Errorf("expected GTIN from upc, got %q", details.GTIN)
+	}
+	if len(details.Images) != 3 {
+		t.Errorf("expected 3 images, got %d", len(details.Images))
+	}
+	if len(details.Variants) != 2 || details.Variants[1].Specifics["Style"] != "USB-C" {
+		t.Errorf("unexpected variants %+v", details.Variants)
+	}
+	if len(details.Offers) != 2 {
+		t.Fatalf("expected 2 offers, got %d", len(details.Offers))
+	}
+	if details.Offers[0].Fulfillment != domain.FulfilledByRetailer {
+		t.Errorf("expected first-party offer fulfilled by retailer, got %q", details.Offers[0].Fulfillment)
+	}
+	used := details.Offers[1]
+	if used.Seller != "Gadget Outlet" || used.Condition != domain.ConditionUsed || used.Shipping != 3.99 {
+		t.Errorf("unexpected used offer %+v", used)
+	}
+	if used.SellerRating != 97 || used.SellerRatings != 1520 || used.Fulfillment != domain.FulfilledBySeller {
+		t.Errorf("unexpected seller details %+v", used)
+	}
+	if used.Title != details.Title || used.Retailer != domain.Amazon || used.ProductID != "B0BDHWDR12" {
+		t.Errorf("offer not linked to product: %+v", used)
+	}
+}
+
+func TestProductClient_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewProductClient(server.URL).Details(context.Background(), domain.Walmart, "123456789")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
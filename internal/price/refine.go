@@ -0,0 +1,282 @@
+package price
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// Filter reports whether an offer should be kept.
+type Filter func(domain.Offer) bool
+
+// MinPrice keeps offers whose effective price is at or above p.
+func MinPrice(p float64) Filter {
+	return func(o domain.Offer) bool { return o.EffectivePrice() >= p }
+}
+
+// MaxPrice keeps offers whose effective price is at or below p.
+func MaxPrice(p float64) Filter {
+	return func(o domain.Offer) bool { return o.EffectivePrice() <= p }
+}
+
+// WithCondition keeps offers in condition c. Offers that do not state a
+// condition are treated as new.
+func WithCondition(c domain.Condition) Filter {
+	return func(o domain.Offer) bool {
+		cond := o.Condition
+		if cond == "" {
+			cond = domain.ConditionNew
+		}
+		return cond == c
+	}
+}
+
+// InStock keeps offers not reported as out of stock.
+func InStock() Filter {
+	return func(o domain.Offer) bool { return !o.OutOfStock }
+}
+
+// PrimeOnly keeps Prime-eligible offers.
+func PrimeOnly() Filter {
+	return func(o domain.Offer) bool { return o.Prime }
+}
+
+// FulfilledBy keeps offers shipped by f.
+func FulfilledBy(f domain.Fulfillment) Filter {
+	return func(o domain.Offer) bool { return o.Fulfillment == f }
+}
+
+// MinRating keeps offers rated at least r stars.
+func MinRating(r float64) Filter {
+	return func(o domain.Offer) bool { return o.Rating >= r }
+}
+
+// ApplyFilters returns the offers accepted by every filter, in order.
+func ApplyFilters(offers []domain.Offer, filters ...Filter) []domain.Offer {
+	var kept []domain.Offer
+NEXT:
+	for _, o := range offers {
+		for _, f := range filters {
+			if !f(o) {
+				continue NEXT
+			}
+		}
+		kept = append(kept, o)
+	}
+	return kept
+}
+
+// SortKey names the field offers are ordered by.
+type SortKey string
+
+const (
+	SortPrice     SortKey = "price"
+	SortLanded    SortKey = "landed"
+	SortRating    SortKey = "rating"
+	SortReviews   SortKey = "reviews"
+	SortRelevance SortKey = "relevance"
+	SortRetailer  SortKey = "retailer"
+	SortDeal      SortKey = "deal"
+)
+
+// sortKeys lists the valid keys in the order they are documented.
+var sortKeys = []SortKey{SortPrice, SortLanded, SortRating, SortReviews, SortRelevance, SortRetailer, SortDeal}
+
+// ParseSortKey validates a sort key name, case-insensitively. An empty name
+// means SortPrice.
+func ParseSortKey(s string) (SortKey, error) {
+	if s == "" {
+		return SortPrice, nil
+	}
+	for _, k := range sortKeys {
+		if strings.EqualFold(s, string(k)) {
+			return k, nil
+		}
+	}
+	names := make([]string, len(sortKeys))
+	for i, k := range sortKeys {
+		names[i] = string(k)
+	}
+	return "", fmt.Errorf("unknown sort key %q (want one of %s)", s, strings.Join(names, ", "))
+}
+
+// ParseCondition validates a condition name, case-insensitively. An empty
+// name means any condition.
+func ParseCondition(s string) (domain.Condition, error) {
+	switch c := domain.Condition(strings.ToLower(s)); c {
+	case "", domain.ConditionNew, domain.ConditionUsed, domain.ConditionRefurbished:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown condition %q (want new, used or refurbished)", s)
+}
+
+// ParseFulfillment validates a fulfillment name, case-insensitively. An empty
+// name means any fulfillment.
+func ParseFulfillment(s string) (domain.Fulfillment, error) {
+	switch f := domain.Fulfillment(strings.ToLower(s)); f {
+	case "", domain.FulfilledByRetailer, domain.FulfilledBySeller:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown fulfillment %q (want retailer or seller)", s)
+}
+
+// Ranker orders offers by a key, ascending unless Desc is set. Prices in
+// different currencies cannot be compared, so sorting by price or landed cost
+// groups the offers by currency first, US dollars first. Ties are broken by
+// currency, price, retailer, title and URL, always ascending, so the order is
+// fully deterministic.
+type Ranker struct {
+	Key  SortKey
+	Desc bool
+}
+
+// Sort orders offers in place.
+func (r Ranker) Sort(offers []domain.Offer) {
+	byPrice := r.Key == "" || r.Key == SortPrice || r.Key == SortLanded
+	sort.SliceStable(offers, func(i, j int) bool {
+		if byPrice {
+			if c := compareCurrency(offers[i], offers[j]); c != 0 {
+				return c < 0
+			}
+		}
+		if c := r.compareKey(offers[i], offers[j]); c != 0 {
+			if r.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return compareTieBreak(offers[i], offers[j]) < 0
+	})
+}
+
+func (r Ranker) compareKey(a, b domain.Offer) int {
+	switch r.Key {
+	case SortLanded:
+		return compareFloat(a.Landed(), b.Landed())
+	case SortRating:
+		return compareFloat(a.Rating, b.Rating)
+	case SortReviews:
+		return compareFloat(float64(a.Reviews), float64(b.Reviews))
+	case SortRelevance:
+		return compareFloat(a.Relevance, b.Relevance)
+	case SortRetailer:
+		return strings.Compare(string(a.Retailer), string(b.Retailer))
+	case SortDeal:
+		return compareFloat(float64(a.Deal.Score), float64(b.Deal.Score))
+	default:
+		return compareFloat(a.EffectivePrice(), b.EffectivePrice())
+	}
+}
+
+func compareTieBreak(a, b domain.Offer) int {
+	if c := compareCurrency(a, b); c != 0 {
+		return c
+	}
+	if c := compareFloat(a.EffectivePrice(), b.EffectivePrice()); c != 0 {
+		return c
+	}
+	if c := strings.Compare(string(a.Retailer), string(b.Retailer)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Title, b.Title); c != 0 {
+		return c
+	}
+	return strings.Compare(a.URL, b.URL)
+}
+
+// compareCurrency orders offers by currency, in the order the currencies'
+// first marketplaces are listed in domain.Marketplaces.
+func compareCurrency(a, b domain.Offer) int {
+	return currencyRank(a.Currency()) - currencyRank(b.Currency())
+}
+
+func currencyRank(currency string) int {
+	markets := domain.Marketplaces()
+	for i, m := range markets {
+		if m.Currency == currency {
+			return i
+		}
+	}
+	return len(markets)
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Refinement gathers the filter and ordering options a caller asked for, so
+// the CLI and any other front end can share one description of them. Zero
+// values mean "no constraint".
+type Refinement struct {
+	MinPrice    float64
+	MaxPrice    float64
+	Condition   domain.Condition
+	InStock     bool
+	PrimeOnly   bool
+	MinRating   float64
+	Fulfillment domain.Fulfillment
+	Sort        SortKey
+	Desc        bool
+}
+
+// Check reports price bounds that cannot apply to offers from retailers,
+// since their marketplaces price in different currencies.
+func (r Refinement) Check(retailers []domain.Retailer) error {
+	if r.MinPrice <= 0 && r.MaxPrice <= 0 {
+		return nil
+	}
+	var currencies []string
+	for _, retailer := range retailers {
+		if c := retailer.Marketplace().Currency; !slices.Contains(currencies, c) {
+			currencies = append(currencies, c)
+		}
+	}
+	if len(currencies) > 1 {
+		sort.Strings(currencies)
+		return fmt.Errorf("price limits need marketplaces with a single currency, not %s", strings.Join(currencies, " and "))
+	}
+	return nil
+}
+
+// Filters returns the filters described by r.
+func (r Refinement) Filters() []Filter {
+	var filters []Filter
+	if r.MinPrice > 0 {
+		filters = append(filters, MinPrice(r.MinPrice))
+	}
+	if r.MaxPrice > 0 {
+		filters = append(filters, MaxPrice(r.MaxPrice))
+	}
+	if r.Condition != "" {
+		filters = append(filters, WithCondition(r.Condition))
+	}
+	if r.InStock {
+		filters = append(filters, InStock())
+	}
+	if r.PrimeOnly {
+		filters = append(filters, PrimeOnly())
+	}
+	if r.MinRating > 0 {
+		filters = append(filters, MinRating(r.MinRating))
+	}
+	if r.Fulfillment != "" {
+		filters = append(filters, FulfilledBy(r.Fulfillment))
+	}
+	return filters
+}
+
+// Apply filters offers and sorts the survivors.
+func (r Refinement) Apply(offers []domain.Offer) []domain.Offer {
+	kept := ApplyFilters(offers, r.Filters()...)
+	Ranker{Key: r.Sort, Desc: r.Desc}.Sort(kept)
+	return kept
+}
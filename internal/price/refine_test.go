@@ -0,0 +1,141 @@
+package price
+
+import (
+	"reflect"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func titles(offers []domain.Offer) []string {
+	var ts []string
+	for _, o := range offers {
+		ts = append(ts, o.Title)
+	}
+	return ts
+}
+
+func TestRefinement_Filters(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "A", Price: 5, Rating: 4.5, Prime: true},
+		{Title: "B", Price: 15, Rating: 3.9, Prime: true},
+		{Title: "C", Price: 25, Rating: 4.8, Condition: domain.ConditionUsed},
+		{Title: "D", Price: 35, Rating: 4.2, Prime: true, OutOfStock: true},
+		{Title: "E", Price: 45, Rating: 4.7, Prime: true},
+	}
+
+	tests := []struct {
+		name string
+		r    Refinement
+		want []string
+	}{
+		{"none", Refinement{}, []string{"A", "B", "C", "D", "E"}},
+		{"price range", Refinement{MinPrice: 10, MaxPrice: 40}, []string{"B", "C", "D"}},
+		{"new only", Refinement{Condition: domain.ConditionNew}, []string{"A", "B", "D", "E"}},
+		{"in stock", Refinement{InStock: true}, []string{"A", "B", "C", "E"}},
+		{"prime and rating", Refinement{PrimeOnly: true, MinRating: 4}, []string{"A", "D", "E"}},
+		{"combined", Refinement{MinPrice: 10, InStock: true, PrimeOnly: true, MinRating: 4}, []string{"E"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titles(tt.r.Apply(append([]domain.Offer(nil), offers...)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRanker_Sort(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "x", Price: 10, Shipping: 5, Rating: 4, Reviews: 10, Retailer: domain.Walmart, Deal: domain.Deal{Score: 40}},
+		{Title: "y", Price: 12, Rating: 4, Reviews: 300, Retailer: domain.Amazon, Deal: domain.Deal{Score: 80}},
+		{Title: "z", Price: 10, Rating: 5, Reviews: 10, Retailer: domain.Amazon, Deal: domain.Deal{Score: 40}},
+		{Title: "w", Price: 10, Shipping: 5, Rating: 4, Reviews: 10, Retailer: domain.Walmart},
+	}
+
+	tests := []struct {
+		r    Ranker
+		want []string
+	}{
+		{Ranker{Key: SortPrice}, []string{"z", "w", "x", "y"}},
+		{Ranker{Key: SortPrice, Desc: true}, []string{"y", "z", "w", "x"}},
+		{Ranker{Key: SortLanded}, []string{"z", "y", "w", "x"}},
+		{Ranker{Key: SortRating, Desc: true}, []string{"z", "w", "x", "y"}},
+		{Ranker{Key: SortReviews, Desc: true}, []string{"y", "z", "w", "x"}},
+		{Ranker{Key: SortRetailer}, []string{"z", "y", "w", "x"}},
+		{Ranker{Key: SortDeal, Desc: true}, []string{"y", "z", "x", "w"}},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.r.Key)
+		if tt.r.Desc {
+			name += " desc"
+		}
+		t.Run(name, func(t *testing.T) {
+			got := append([]domain.Offer(nil), offers...)
+			tt.r.Sort(got)
+			if !reflect.DeepEqual(titles(got), tt.want) {
+				t.Errorf("Sort() = %v, want %v", titles(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestRanker_SortGroupsCurrencies(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "jp", Price: 28000, Retailer: domain.AmazonJP},
+		{Title: "us expensive", Price: 199, Retailer: domain.Amazon},
+		{Title: "uk", Price: 150, Retailer: domain.AmazonUK},
+		{Title: "us cheap", Price: 189, Retailer: domain.Walmart},
+	}
+	Ranker{Key: SortPrice}.Sort(offers)
+	if got, want := titles(offers), []string{"us cheap", "us expensive", "uk", "jp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort(price) = %v, want %v", got, want)
+	}
+	Ranker{Key: SortPrice, Desc: true}.Sort(offers)
+	if got, want := titles(offers), []string{"us expensive", "us cheap", "uk", "jp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort(price, desc) = %v, want %v", got, want)
+	}
+}
+
+func TestRefinement_Check(t *testing.T) {
+	mixed := []domain.Retailer{domain.Amazon, domain.AmazonJP}
+	if err := (Refinement{MaxPrice: 200}).Check(mixed); err == nil {
+		t.Error("Check(USD and JPY) error = nil, want price limits rejected")
+	}
+	if err := (Refinement{Sort: SortPrice}).Check(mixed); err != nil {
+		t.Errorf("Check() without price limits error = %v", err)
+	}
+	if err := (Refinement{MinPrice: 50}).Check([]domain.Retailer{domain.Amazon, domain.Walmart}); err != nil {
+		t.Errorf("Check(USD only) error = %v", err)
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	if k, err := ParseSortKey("Landed"); err != nil || k != SortLanded {
+		t.Errorf("ParseSortKey(Landed) = %q, %v", k, err)
+	}
+	if k, err := ParseSortKey(""); err != nil || k != SortPrice {
+		t.Errorf("ParseSortKey(\"\") = %q, %v", k, err)
+	}
+	if _, err := ParseSortKey("cheapest"); err == nil {
+		t.Errorf("ParseSortKey(cheapest) should fail")
+	}
+}
+
+func TestParseConditionAndFulfillment(t *testing.T) {
+	if c, err := ParseCondition("NEW"); err != nil || c != domain.ConditionNew {
+		t.Errorf("ParseCondition(NEW) = %q, %v", c, err)
+	}
+	if _, err := ParseCondition("mint"); err == nil {
+		t.Errorf("ParseCondition(mint) should fail")
+	}
+	if f, err := ParseFulfillment("Retailer"); err != nil || f != domain.FulfilledByRetailer {
+		t.Errorf("ParseFulfillment(Retailer) = %q, %v", f, err)
+	}
+	if _, err := ParseFulfillment("drone"); err == nil {
+		t.Errorf("ParseFulfillment(drone) should fail")
+	}
+}
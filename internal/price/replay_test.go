@@ -0,0 +1,86 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/httpfixture"
+)
+
+// fixtureClient replays testdata/zinc/<name>.json, or records it again when
+// SAVVY_RECORD=1.
+func fixtureClient(t *testing.T, name string) *http.Client {
+	t.Helper()
+	rec, err := httpfixture.New(filepath.Join("testdata", "zinc", name+".json"), httpfixture.ModeFromEnv())
+	if err != nil {
+		t.Fatalf("httpfixture.New error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+	return rec.Client()
+}
+
+func TestReplay_AmazonSearch(t *testing.T) {
+	s := NewAmazonSearcher(DefaultZincBaseURL+"/search/amazon", fixtureClient(t, "search_amazon"))
+	offers, err := s.Search(context.Background(), "airpods pro")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(offers) != 4 {
+		t.Fatalf("expected 4 offers, got %d", len(offers))
+	}
+
+	first := offers[0]
+	if first.ProductID != "B0D1XD1ZV3" || first.GTIN != "195949704581" || first.Model != "MTJV3AM/A" || first.Reviews != 21873 || !first.Prime || first.OutOfStock {
+		t.Errorf("unexpected first offer %+v", first)
+	}
+	if renewed := offers[1]; renewed.Condition != domain.ConditionRefurbished || renewed.Shipping != 4.99 || renewed.Landed() != 147.49 {
+		t.Errorf("unexpected refurbished offer %+v", renewed)
+	}
+	// gtin wins over upc, and available=false marks the offer out of stock.
+	if magsafe := offers[2]; magsafe.GTIN != "00195949052484" || !magsafe.OutOfStock {
+		t.Errorf("unexpected MagSafe offer %+v", magsafe)
+	}
+	if bare := offers[3]; bare.Rating != 0 || bare.Condition != "" || bare.OutOfStock {
+		t.Errorf("missing fields should stay zero: %+v", bare)
+	}
+}
+
+func TestReplay_SearchPrices(t *testing.T) {
+	searchers := map[domain.Retailer]Searcher{
+		domain.Amazon:  NewAmazonSearcher(DefaultZincBaseURL+"/search/amazon", fixtureClient(t, "search_amazon")),
+		domain.Walmart: NewWalmartSearcher(DefaultZincBaseURL+"/search/walmart", fixtureClient(t, "search_walmart")),
+	}
+	offers, err := SearchPrices(context.Background(), "airpods pro", searchers)
+	if err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+	// Three from Amazon, two from Walmart, cheapest first.
+	if len(offers) != 5 || offers[0].Price != 99.95 || offers[0].Retailer != domain.Walmart {
+		t.Errorf("unexpected merged offers %v", titles(offers))
+	}
+}
+
+func TestReplay_ProductLookup(t *testing.T) {
+	c := NewProductClient(DefaultZincBaseURL, fixtureClient(t, "product_walmart"))
+	details, err := c.Lookup(context.Background(), domain.Walmart, "5689919121")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if details.Brand != "Apple" || details.Reviews != 9102 || len(details.Variants) != 0 || len(details.Offers) != 2 {
+		t.Errorf("unexpected details %+v", details)
+	}
+	seller := details.Offers[1]
+	if seller.Seller != "TechDeals" || seller.Fulfillment != domain.FulfilledBySeller || seller.Shipping != 6.99 || seller.SellerRating != 92 {
+		t.Errorf("unexpected marketplace offer %+v", seller)
+	}
+	if walmart := details.Offers[0]; walmart.Fulfillment != domain.FulfilledByRetailer || walmart.Title != details.Title {
+		t.Errorf("unexpected first-party offer %+v", walmart)
+	}
+}
@@ -4,95 +4,127 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
-	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"savvyshopper/domain"
+	"savvyshopper/internal/config"
 )
 
-// SearchPrices queries both Amazon and Walmart concurrently, merges, sorts, and enforces invariants.
-// If searchers is nil, uses the default real searchers.
-func SearchPrices(ctx context.Context, query string, searchersOpt ...map[domain.Retailer]Searcher) ([]domain.Offer, error) {
-	var searchers map[domain.Retailer]Searcher
-	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
-		searchers = searchersOpt[0]
-	} else {
-		searchers = map[domain.Retailer]Searcher{
-			domain.Amazon:  NewAmazonSearcher("https://api.zinc.io/v1/search/amazon"),
-			domain.Walmart: NewWalmartSearcher("https://api.zinc.io/v1/search/walmart"),
+// NewSearchers returns a Zinc-backed searcher for each marketplace cfg
+// selects, behind a circuit breaker, tagging offer URLs
+// with the marketplace's affiliate tag if cfg has one. Marketplaces without
+// a path are skipped. cfg should already be validated; an invalid
+// marketplace list falls back to domain.DefaultMarketplaces.
+// If clientOpt is provided, requests go through that client.
+func NewSearchers(cfg config.Zinc, clientOpt ...*http.Client) map[domain.Retailer]Searcher {
+	retailers, err := cfg.Retailers()
+	if err != nil {
+		retailers = domain.DefaultMarketplaces
+	}
+	searchers := make(map[domain.Retailer]Searcher)
+	tags := cfg.Tags()
+	for _, retailer := range retailers {
+		endpoint := cfg.SearchURL(retailer)
+		if endpoint == "" {
+			continue
+		}
+		s := NewSearcher(retailer, endpoint, clientOpt...)
+		if tag := tags[retailer]; tag != "" {
+			s = NewAffiliateSearcher(retailer, s, tag)
 		}
+		searchers[retailer] = NewBreakerSearcher(retailer, s, defaultBreakerThreshold, defaultBreakerCooldown)
 	}
+	return searchers
+}
 
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
+// DefaultSearchers returns the searchers for the default Zinc settings
+// overlaid with the ZINC_* environment variables. Commands that accept a
+// config file build theirs with NewSearchers instead.
+func DefaultSearchers() map[domain.Retailer]Searcher {
+	return NewSearchers(config.Config{Zinc: config.DefaultZinc()}.WithEnv().Zinc)
+}
 
-	type result struct {
-		retailer domain.Retailer
-		offers   []domain.Offer
-		err      error
-	}
-	ch := make(chan result, len(searchers))
-	var wg sync.WaitGroup
+// RetailerResult reports how one retailer's part of a search went.
+type RetailerResult struct {
+	Retailer domain.Retailer
+	// Offers is the number of offers the retailer returned.
+	Offers int
+	// Err is set if the retailer's search failed.
+	Err error
+	// Elapsed is the time from the start of the search to the retailer's
+	// answer.
+	Elapsed time.Duration
+}
 
-	for retailer, s := range searchers {
-		wg.Add(1)
-		go func(retailer domain.Retailer, s Searcher) {
-			defer wg.Done()
-			offers, err := s.Search(ctx, query)
-			ch <- result{retailer: retailer, offers: offers, err: err}
-		}(retailer, s)
-	}
+// SearchResult is a finished search across retailers.
+type SearchResult struct {
+	Offers []domain.Offer
+	// Retailers lists every retailer searched, by name.
+	Retailers []RetailerResult
+	// Started is when the search began; Elapsed is how long it took.
+	Started time.Time
+	Elapsed time.Duration
+}
+
+// SearchPrices queries the configured marketplaces concurrently, merges
+// their offers, up to maxOffersPerRetailer from each, sorts them by price and
+// enforces invariants.
+// It collects the events from StreamPrices.
+// If searchers is nil, uses the default real searchers.
+func SearchPrices(ctx context.Context, query string, searchersOpt ...map[domain.Retailer]Searcher) ([]domain.Offer, error) {
+	res, err := Search(ctx, query, searchersOpt...)
+	return res.Offers, err
+}
 
-	// Close channel after all goroutines finish
-	go func() {
-		wg.Wait()
-		close(ch)
+// Search is SearchPrices, also reporting how each retailer answered and how
+// long the search took. On error the result still has the retailers that
+// answered.
+func Search(ctx context.Context, query string, searchersOpt ...map[domain.Retailer]Searcher) (res SearchResult, err error) {
+	ctx, span := tracer.Start(ctx, "SearchPrices", trace.WithAttributes(attribute.String("query", query)))
+	defer func() {
+		span.SetAttributes(attribute.Int("offers", len(res.Offers)))
+		endSpan(span, err)
 	}()
 
+	res.Started = time.Now()
 	var allOffers []domain.Offer
 	var firstErr error
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("%w: search timed out", domain.ErrNetwork)
-		case res, ok := <-ch:
-			if !ok {
-				goto DONE
+	for ev := range StreamPrices(ctx, query, searchersOpt...) {
+		switch ev.Kind {
+		case EventFailed:
+			res.Retailers = append(res.Retailers, RetailerResult{Retailer: ev.Retailer, Err: ev.Err, Elapsed: ev.Elapsed})
+			if firstErr == nil && (errors.Is(ev.Err, domain.ErrNetwork) || errors.Is(ev.Err, domain.ErrAuth)) {
+				firstErr = ev.Err
 			}
-			if res.err != nil {
-				if firstErr == nil && (errors.Is(res.err, domain.ErrNetwork) || errors.Is(res.err, domain.ErrAuth)) {
-					firstErr = res.err
-				}
-				continue
+		case EventOffers:
+			res.Retailers = append(res.Retailers, RetailerResult{Retailer: ev.Retailer, Offers: len(ev.Offers), Elapsed: ev.Elapsed})
+			allOffers = append(allOffers, ev.Offers...)
+		case EventDone:
+			res.Elapsed = ev.Elapsed
+			if ev.Err != nil {
+				return res, ev.Err
 			}
-			// Set retailer field (defensive, in case helpers don't)
-			for i := range res.offers {
-				res.offers[i].Retailer = res.retailer
-			}
-			if len(res.offers) > 3 {
-				res.offers = res.offers[:3]
-			}
-			allOffers = append(allOffers, res.offers...)
 		}
 	}
-DONE:
+	sort.Slice(res.Retailers, func(i, j int) bool { return res.Retailers[i].Retailer < res.Retailers[j].Retailer })
+
 	if len(allOffers) == 0 {
 		if firstErr != nil {
-			return nil, firstErr
+			return res, firstErr
 		}
-		return nil, domain.ErrNoResults
-	}
-	if len(allOffers) > 6 {
-		allOffers = allOffers[:6]
+		return res, domain.ErrNoResults
 	}
-	sort.Slice(allOffers, func(i, j int) bool {
-		return allOffers[i].Price < allOffers[j].Price
-	})
+	Ranker{Key: SortPrice}.Sort(allOffers)
 	for _, offer := range allOffers {
 		if offer.Price < 0 {
-			return nil, fmt.Errorf("%w: negative price found", domain.ErrNetwork)
+			return res, fmt.Errorf("%w: negative price found", domain.ErrNetwork)
 		}
 	}
-	return allOffers, nil
+	res.Offers = allOffers
+	return res, nil
 }
@@ -2,12 +2,19 @@ package price
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"savvyshopper/domain"
+	"savvyshopper/internal/config"
 )
 
 type mockSearcher struct {
@@ -60,6 +67,34 @@ func TestSearchPrices_MergeSortAndTruncate(t *testing.T) {
 	}
 }
 
+func TestSearchPrices_AllMarketplacesKept(t *testing.T) {
+	offers := func(retailer domain.Retailer, prices ...float64) []domain.Offer {
+		var out []domain.Offer
+		for _, p := range prices {
+			out = append(out, domain.Offer{Title: string(retailer), Price: p, Retailer: retailer})
+		}
+		return out
+	}
+	// The cheapest offers arrive last.
+	searchers := map[domain.Retailer]Searcher{
+		domain.Amazon:   &mockSearcher{results: offers(domain.Amazon, 50, 60, 70)},
+		domain.AmazonUK: &mockSearcher{results: offers(domain.AmazonUK, 40, 45, 90)},
+		domain.AmazonDE: &mockSearcher{results: offers(domain.AmazonDE, 1, 2, 3), latency: 30 * time.Millisecond},
+	}
+	results, err := SearchPrices(context.Background(), "test", searchers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var prices []float64
+	for _, o := range results {
+		prices = append(prices, o.Price)
+	}
+	// Grouped by currency, dollars, pounds then euros, cheapest first in each.
+	if want := []float64{50, 60, 70, 40, 45, 90, 1, 2, 3}; !slices.Equal(prices, want) {
+		t.Errorf("prices = %v, want every marketplace's offers %v", prices, want)
+	}
+}
+
 func TestSearchPrices_NoResults(t *testing.T) {
 	searchers := map[domain.Retailer]Searcher{
 		domain.Amazon:  &mockSearcher{results: nil},
@@ -72,6 +107,29 @@ func TestSearchPrices_NoResults(t *testing.T) {
 	}
 }
 
+func TestSearch_RetailerResults(t *testing.T) {
+	searchers := map[domain.Retailer]Searcher{
+		domain.Walmart: &mockSearcher{err: domain.ErrNetwork, latency: 10 * time.Millisecond},
+		domain.Amazon:  &mockSearcher{results: []domain.Offer{{Title: "A", Price: 1}, {Title: "B", Price: 2}}},
+	}
+	res, err := Search(context.Background(), "test", searchers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Offers) != 2 || res.Started.IsZero() || res.Elapsed < 10*time.Millisecond {
+		t.Errorf("Search() = %+v", res)
+	}
+	if len(res.Retailers) != 2 {
+		t.Fatalf("Retailers = %+v, want 2", res.Retailers)
+	}
+	if r := res.Retailers[0]; r.Retailer != domain.Amazon || r.Offers != 2 || r.Err != nil {
+		t.Errorf("Retailers[0] = %+v", r)
+	}
+	if r := res.Retailers[1]; r.Retailer != domain.Walmart || !errors.Is(r.Err, domain.ErrNetwork) || r.Elapsed < 10*time.Millisecond {
+		t.Errorf("Retailers[1] = %+v", r)
+	}
+}
+
 func TestSearchPrices_ConcurrentLatency(t *testing.T) {
 	// Each searcher sleeps 40ms; total should be just over 40ms, not 80ms+
 	searchers := map[domain.Retailer]Searcher{
@@ -88,3 +146,116 @@ func TestSearchPrices_ConcurrentLatency(t *testing.T) {
 		t.Errorf("concurrent SearchPrices took too long: %v (should be <60ms)", elapsed)
 	}
 }
+
+func TestNewSearchers_Endpoints(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"results": [{"title": "Echo Dot", "price": 49.99, "url": "https://example.com/p"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultZinc().Merge(config.Zinc{
+		BaseURL: srv.URL + "/v1",
+		Paths:   map[string]string{"walmart": "/staging/walmart"},
+	})
+	searchers := NewSearchers(cfg, srv.Client())
+	if len(searchers) != 2 {
+		t.Fatalf("NewSearchers() returned %d searchers, want 2", len(searchers))
+	}
+	if _, err := SearchPrices(context.Background(), "echo dot", searchers); err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+	sort.Strings(paths)
+	want := []string{"/v1/search/amazon", "/v1/staging/walmart"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("requested paths = %v, want %v", paths, want)
+	}
+}
+
+func TestNewSearchers_NotCached(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.Write([]byte(`{"results": [{"title": "Echo Dot", "price": 49.99, "url": "https://example.com/p"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultZinc().Merge(config.Zinc{BaseURL: srv.URL, Marketplaces: []string{"amazon.com"}})
+	searchers := NewSearchers(cfg, srv.Client())
+	for range 2 {
+		if _, err := SearchPrices(context.Background(), "echo dot", searchers); err != nil {
+			t.Fatalf("SearchPrices() error = %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Zinc got %d requests for 2 searches, want every search sent", requests)
+	}
+}
+
+func TestNewSearchers_Marketplaces(t *testing.T) {
+	var mu sync.Mutex
+	markets := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload zincPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		markets[r.URL.Path] = payload.Retailer + " " + payload.Marketplace
+		mu.Unlock()
+		w.Write([]byte(`{"results": [{"title": "Echo Dot", "price": 49.99, "url": "https://example.com/p"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultZinc().Merge(config.Zinc{BaseURL: srv.URL, Marketplaces: []string{"amazon.co.uk", "amazon.de"}})
+	offers, err := SearchPrices(context.Background(), "echo dot", NewSearchers(cfg, srv.Client()))
+	if err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+	want := map[string]string{
+		"/search/amazon_uk": "Amazon amazon.co.uk",
+		"/search/amazon_de": "Amazon amazon.de",
+	}
+	if !maps.Equal(markets, want) {
+		t.Errorf("requests = %v, want %v", markets, want)
+	}
+	for _, o := range offers {
+		if o.Retailer != domain.AmazonUK && o.Retailer != domain.AmazonDE {
+			t.Errorf("offer retailer = %s, want a marketplace retailer", o.Retailer)
+		}
+	}
+}
+
+func TestNewSearchers_AffiliateTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search/amazon" {
+			w.Write([]byte(`{"results": [{"title": "Echo Dot", "price": 49.99, "product_id": "B09B8V1LZ3", "url": "https://www.amazon.com/Echo-Dot/dp/B09B8V1LZ3/ref=sr_1_1?qid=1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results": [{"title": "Echo Dot", "price": 44.99, "url": "https://www.walmart.com/ip/Echo-Dot/123456789?athbdg=L1600"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultZinc().Merge(config.Zinc{BaseURL: srv.URL, AffiliateTags: map[string]string{"amazon.com": "team-20"}})
+	offers, err := SearchPrices(context.Background(), "echo dot", NewSearchers(cfg, srv.Client()))
+	if err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+	want := map[domain.Retailer]string{
+		domain.Amazon:  "https://www.amazon.com/dp/B09B8V1LZ3?tag=team-20",
+		domain.Walmart: "https://www.walmart.com/ip/123456789",
+	}
+	for _, o := range offers {
+		if o.URL != want[o.Retailer] {
+			t.Errorf("%s URL = %q, want %q", o.Retailer, o.URL, want[o.Retailer])
+		}
+	}
+	if len(offers) != 2 || offers[0].ProductID != "123456789" {
+		t.Errorf("offers = %+v, want the Walmart item ID taken from its URL", offers)
+	}
+}
@@ -2,55 +2,61 @@ package price
 
 import (
 	"context"
+	"net/http"
 
 	"savvyshopper/domain"
+	"savvyshopper/internal/config"
 )
 
+// clientFrom returns the client given as an optional argument, or a new one
+// with config.RequestTimeout.
+func clientFrom(clientOpt []*http.Client) *http.Client {
+	if len(clientOpt) > 0 && clientOpt[0] != nil {
+		return clientOpt[0]
+	}
+	return &http.Client{Timeout: config.RequestTimeout}
+}
+
 // Searcher defines the interface for searching offers.
 type Searcher interface {
 	Search(ctx context.Context, query string) ([]domain.Offer, error)
 }
 
-// amazonSearcher implements the Searcher interface for Amazon.
-type amazonSearcher struct {
-	endpoint string
-}
-
-// walmartSearcher implements the Searcher interface for Walmart.
-type walmartSearcher struct {
+// zincSearcher implements the Searcher interface for one marketplace through
+// the Zinc API.
+type zincSearcher struct {
+	retailer domain.Retailer
 	endpoint string
+	client   *http.Client
 }
 
-// NewAmazonSearcher creates a new amazonSearcher instance.
-func NewAmazonSearcher(endpoint string) Searcher {
-	return &amazonSearcher{endpoint: endpoint}
+// NewSearcher creates a Searcher for retailer's marketplace that posts to
+// endpoint.
+// If clientOpt is provided, requests are sent with that client.
+func NewSearcher(retailer domain.Retailer, endpoint string, clientOpt ...*http.Client) Searcher {
+	return &zincSearcher{retailer: retailer, endpoint: endpoint, client: clientFrom(clientOpt)}
 }
 
-// NewWalmartSearcher creates a new walmartSearcher instance.
-func NewWalmartSearcher(endpoint string) Searcher {
-	return &walmartSearcher{endpoint: endpoint}
+// NewAmazonSearcher creates a Searcher for amazon.com.
+// If clientOpt is provided, requests are sent with that client.
+func NewAmazonSearcher(endpoint string, clientOpt ...*http.Client) Searcher {
+	return NewSearcher(domain.Amazon, endpoint, clientOpt...)
 }
 
-// Search for amazonSearcher.
-func (s *amazonSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
-	// Build payload
-	payload, err := buildPayload(query, domain.Amazon)
-	if err != nil {
-		return nil, err
-	}
-
-	// Make request
-	return makeRequest(ctx, s.endpoint, payload, domain.Amazon)
+// NewWalmartSearcher creates a Searcher for walmart.com.
+// If clientOpt is provided, requests are sent with that client.
+func NewWalmartSearcher(endpoint string, clientOpt ...*http.Client) Searcher {
+	return NewSearcher(domain.Walmart, endpoint, clientOpt...)
 }
 
-// Search for walmartSearcher.
-func (s *walmartSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+// Search for zincSearcher.
+func (s *zincSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
 	// Build payload
-	payload, err := buildPayload(query, domain.Walmart)
+	payload, err := buildPayload(query, s.retailer)
 	if err != nil {
 		return nil, err
 	}
 
 	// Make request
-	return makeRequest(ctx, s.endpoint, payload, domain.Walmart)
+	return makeRequest(ctx, s.client, s.endpoint, payload, s.retailer)
 }
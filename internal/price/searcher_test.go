@@ -1,13 +1,18 @@
 package price
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"savvyshopper/domain"
+	"savvyshopper/internal/logging"
 )
 
 func TestAmazonSearcher(t *testing.T) {
@@ -42,11 +47,7 @@ func TestAmazonSearcher(t *testing.T) {
 
 		// Send mock response
 		response := zincResponse{
-			Results: []struct {
-				Title string  `json:"title"`
-				Price float64 `json:"price"`
-				URL   string  `json:"url"`
-			}{
+			Results: []zincResult{
 				{
 					Title: "Test Product",
 					Price: 19.99,
@@ -117,11 +118,7 @@ func TestWalmartSearcher(t *testing.T) {
 
 		// Send mock response
 		response := zincResponse{
-			Results: []struct {
-				Title string  `json:"title"`
-				Price float64 `json:"price"`
-				URL   string  `json:"url"`
-			}{
+			Results: []zincResult{
 				{
 					Title: "Test Product",
 					Price: 29.99,
@@ -159,3 +156,75 @@ func TestWalmartSearcher(t *testing.T) {
 		t.Errorf("expected retailer 'Walmart', got %s", offers[0].Retailer)
 	}
 }
+
+func TestSearcher_Promotions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results": [{
+			"title": "Dish Soap, 6 Pack", "price": 18.99, "list_price": 24.99, "url": "https://example.com/soap",
+			"promotions": [
+				{"type": "coupon", "description": "Save $2 with coupon", "amount_off": 2},
+				{"type": "subscribe_save", "percent_off": 5},
+				{"type": "member_price", "membership": "Prime", "price": 17.49},
+				{"type": "member_price", "membership": "prime", "price": 19.99},
+				{"type": "lightning_deal", "percent_off": 30}
+			]
+		}]}`))
+	}))
+	defer server.Close()
+
+	offers, err := NewAmazonSearcher(server.URL).Search(context.Background(), "dish soap")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	o := offers[0]
+	if o.ListPrice != 24.99 {
+		t.Errorf("ListPrice = %v, want 24.99", o.ListPrice)
+	}
+	want := []domain.Promotion{
+		{Kind: domain.PromotionCoupon, Description: "Save $2 with coupon", AmountOff: 2},
+		{Kind: domain.PromotionSubscribeSave, PercentOff: 5},
+		{Kind: domain.PromotionMember, Membership: domain.MembershipPrime, AmountOff: 18.99 - 17.49},
+	}
+	if !slices.Equal(o.Promotions, want) {
+		t.Errorf("Promotions = %+v, want %+v", o.Promotions, want)
+	}
+}
+
+func TestSearcher_APIKey(t *testing.T) {
+	t.Setenv("ZINC_API_KEY", "sk-test")
+	var user string
+	var ok bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok = r.BasicAuth()
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewAmazonSearcher(server.URL).Search(context.Background(), "echo dot"); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !ok || user != "sk-test" {
+		t.Errorf("Basic Auth user = %q (set %v), want the API key", user, ok)
+	}
+}
+
+func TestSearcher_Logs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(zincResponse{Results: []zincResult{{Title: "Test Product", Price: 19.99}}})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger, _ := logging.New(&buf, slog.LevelDebug, logging.FormatText)
+	ctx := logging.WithContext(context.Background(), logger)
+	if _, err := SearchPrices(ctx, "test", map[domain.Retailer]Searcher{domain.Walmart: NewWalmartSearcher(server.URL)}); err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"msg=\"zinc request\"", "attempt=1", "status=200", "msg=\"zinc response\"", "bytes=", "retailer=Walmart offers=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/logging"
+)
+
+// searchTimeout bounds a whole search across all retailers.
+const searchTimeout = 2 * time.Second
+
+// maxOffersPerRetailer caps how many offers each retailer contributes.
+const maxOffersPerRetailer = 3
+
+// EventKind identifies what a SearchEvent reports.
+type EventKind string
+
+const (
+	// EventStarted is sent once per retailer before its search begins.
+	EventStarted EventKind = "started"
+	// EventOffers carries a retailer's offers.
+	EventOffers EventKind = "offers"
+	// EventFailed reports that a retailer's search returned an error.
+	EventFailed EventKind = "failed"
+	// EventDone is always the last event. Err is set if the search timed
+	// out or was cancelled before every retailer reported.
+	EventDone EventKind = "done"
+)
+
+// SearchEvent reports incremental progress of a streaming search.
+type SearchEvent struct {
+	Kind EventKind
+	// Retailer is empty for EventDone.
+	Retailer domain.Retailer
+	Offers   []domain.Offer
+	Err      error
+	// Elapsed is the time since the search started.
+	Elapsed time.Duration
+}
+
+// StreamPrices queries every retailer concurrently and reports each one's
+// outcome as soon as it arrives. The channel is closed after EventDone.
+// Callers may stop reading at any time; cancel ctx to abandon the in-flight
+// requests.
+// If searchers is nil, uses the default real searchers.
+func StreamPrices(ctx context.Context, query string, searchersOpt ...map[domain.Retailer]Searcher) <-chan SearchEvent {
+	var searchers map[domain.Retailer]Searcher
+	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
+		searchers = searchersOpt[0]
+	} else {
+		searchers = DefaultSearchers()
+	}
+
+	start := time.Now()
+	logger := logging.FromContext(ctx)
+	ctx, span := tracer.Start(ctx, "StreamPrices", trace.WithAttributes(attribute.Int("retailers", len(searchers))))
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+
+	// Room for every event, so senders never block on a consumer that has
+	// gone away.
+	events := make(chan SearchEvent, 2*len(searchers)+1)
+	results := make(chan SearchEvent, len(searchers))
+
+	retailers := make([]domain.Retailer, 0, len(searchers))
+	for retailer := range searchers {
+		retailers = append(retailers, retailer)
+	}
+	sort.Slice(retailers, func(i, j int) bool { return retailers[i] < retailers[j] })
+
+	for _, retailer := range retailers {
+		events <- SearchEvent{Kind: EventStarted, Retailer: retailer}
+		go func(retailer domain.Retailer, s Searcher) {
+			began := time.Now()
+			ctx, span := tracer.Start(ctx, "Searcher.Search", trace.WithAttributes(attribute.String("retailer", string(retailer))))
+			offers, err := s.Search(ctx, query)
+			span.SetAttributes(attribute.Int("offers", len(offers)))
+			endSpan(span, err)
+			if err != nil {
+				logger.Warn("retailer search failed", "retailer", retailer, "duration", time.Since(began), "error", err)
+			} else {
+				logger.Debug("retailer search", "retailer", retailer, "offers", len(offers), "duration", time.Since(began))
+			}
+			retailerRequestDuration.WithLabelValues(string(retailer)).Observe(time.Since(began).Seconds())
+			if err == nil && len(offers) == 0 {
+				retailerRequestsTotal.WithLabelValues(string(retailer), outcome(domain.ErrNoResults)).Inc()
+			} else {
+				retailerRequestsTotal.WithLabelValues(string(retailer), outcome(err)).Inc()
+			}
+			if err != nil {
+				results <- SearchEvent{Kind: EventFailed, Retailer: retailer, Err: err, Elapsed: time.Since(start)}
+				return
+			}
+			// Set retailer field (defensive, in case helpers don't)
+			for i := range offers {
+				offers[i].Retailer = retailer
+			}
+			if len(offers) > maxOffersPerRetailer {
+				offers = offers[:maxOffersPerRetailer]
+			}
+			results <- SearchEvent{Kind: EventOffers, Retailer: retailer, Offers: offers, Elapsed: time.Since(start)}
+		}(retailer, searchers[retailer])
+	}
+
+	go func() {
+		defer close(events)
+		defer cancel()
+		var found, failed bool
+		for pending := len(retailers); pending > 0; pending-- {
+			select {
+			case <-ctx.Done():
+				err := fmt.Errorf("%w: search timed out", domain.ErrNetwork)
+				if errors.Is(ctx.Err(), context.Canceled) {
+					err = fmt.Errorf("%w: search cancelled", domain.ErrNetwork)
+				}
+				logger.Warn("search incomplete", "query", query, "pending", pending, "duration", time.Since(start), "error", err)
+				searchesTotal.WithLabelValues(outcome(ctx.Err())).Inc()
+				searchDuration.Observe(time.Since(start).Seconds())
+				endSpan(span, err)
+				events <- SearchEvent{Kind: EventDone, Err: err, Elapsed: time.Since(start)}
+				return
+			case ev := <-results:
+				found = found || len(ev.Offers) > 0
+				failed = failed || ev.Err != nil
+				events <- ev
+			}
+		}
+		switch {
+		case found:
+			searchesTotal.WithLabelValues(outcome(nil)).Inc()
+		case failed:
+			searchesTotal.WithLabelValues("error").Inc()
+		default:
+			searchesTotal.WithLabelValues(outcome(domain.ErrNoResults)).Inc()
+		}
+		searchDuration.Observe(time.Since(start).Seconds())
+		logger.Debug("search finished", "query", query, "retailers", len(retailers), "duration", time.Since(start))
+		span.End()
+		events <- SearchEvent{Kind: EventDone, Elapsed: time.Since(start)}
+	}()
+
+	return events
+}
@@ -0,0 +1,83 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+)
+
+// blockingSearcher never returns until its context is done.
+type blockingSearcher struct{}
+
+func (blockingSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStreamPrices_FastRetailerFirst(t *testing.T) {
+	searchers := map[domain.Retailer]Searcher{
+		domain.Amazon:  &mockSearcher{results: []domain.Offer{{Title: "A", Price: 1}}, latency: 60 * time.Millisecond},
+		domain.Walmart: &mockSearcher{results: []domain.Offer{{Title: "B", Price: 2}}},
+	}
+
+	var kinds []EventKind
+	var order []domain.Retailer
+	for ev := range StreamPrices(context.Background(), "test", searchers) {
+		kinds = append(kinds, ev.Kind)
+		if ev.Kind == EventOffers {
+			order = append(order, ev.Retailer)
+			if ev.Offers[0].Retailer != ev.Retailer {
+				t.Errorf("offer retailer not set: %+v", ev.Offers[0])
+			}
+		}
+		if ev.Kind == EventDone && ev.Err != nil {
+			t.Errorf("unexpected done error: %v", ev.Err)
+		}
+	}
+
+	want := []EventKind{EventStarted, EventStarted, EventOffers, EventOffers, EventDone}
+	if len(kinds) != len(want) {
+		t.Fatalf("events = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("events = %v, want %v", kinds, want)
+		}
+	}
+	if order[0] != domain.Walmart || order[1] != domain.Amazon {
+		t.Errorf("expected Walmart before slower Amazon, got %v", order)
+	}
+}
+
+func TestStreamPrices_FailureAndCancel(t *testing.T) {
+	searchers := map[domain.Retailer]Searcher{
+		domain.Amazon:  blockingSearcher{},
+		domain.Walmart: &mockSearcher{err: domain.ErrAuth},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	failed := make(map[domain.Retailer]error)
+	var done SearchEvent
+	for ev := range StreamPrices(ctx, "test", searchers) {
+		switch ev.Kind {
+		case EventFailed:
+			failed[ev.Retailer] = ev.Err
+			cancel()
+		case EventDone:
+			done = ev
+		}
+	}
+
+	if !errors.Is(failed[domain.Walmart], domain.ErrAuth) {
+		t.Errorf("expected Walmart to fail with ErrAuth, got %v", failed[domain.Walmart])
+	}
+	// Cancelling either ends the stream early or makes Amazon's search fail,
+	// depending on which the stream notices first.
+	if !errors.Is(done.Err, domain.ErrNetwork) && !errors.Is(failed[domain.Amazon], context.Canceled) {
+		t.Errorf("cancel not reported: done = %v, Amazon = %v", done.Err, failed[domain.Amazon])
+	}
+}
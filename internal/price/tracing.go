@@ -0,0 +1,20 @@
+package price
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the package's spans. It follows whatever global tracer
+// provider is installed, even one installed after the package loads.
+var tracer = otel.Tracer("savvyshopper/internal/price")
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
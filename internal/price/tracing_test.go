@@ -0,0 +1,95 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/telemetry"
+)
+
+func attr(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing_SearchPrices(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := telemetry.Install(exp)
+	defer func() {
+		shutdown(context.Background())
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	}()
+
+	// The first attempt loses its connection, so the request is retried.
+	requests := 0
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		traceparent = r.Header.Get("Traceparent")
+		json.NewEncoder(w).Encode(zincResponse{Results: []zincResult{{Title: "Test Product", Price: 19.99, URL: "https://example.com/p"}}})
+	}))
+	defer server.Close()
+
+	searchers := map[domain.Retailer]Searcher{
+		domain.Amazon: NewCachedSearcher(domain.Amazon, NewAmazonSearcher(server.URL), time.Minute),
+	}
+	if _, err := SearchPrices(context.Background(), "test", searchers); err != nil {
+		t.Fatalf("SearchPrices() error = %v", err)
+	}
+
+	spans := map[string][]tracetest.SpanStub{}
+	for _, s := range exp.GetSpans() {
+		spans[s.Name] = append(spans[s.Name], s)
+	}
+	for _, name := range []string{"SearchPrices", "StreamPrices", "Searcher.Search", "cache.lookup", "zinc.decode"} {
+		if len(spans[name]) != 1 {
+			t.Errorf("expected one %s span, got %d", name, len(spans[name]))
+		}
+	}
+	root := spans["SearchPrices"][0]
+	for _, s := range exp.GetSpans() {
+		if s.SpanContext.TraceID() != root.SpanContext.TraceID() {
+			t.Errorf("span %s is not part of the search trace", s.Name)
+		}
+	}
+	if got := attr(spans["Searcher.Search"][0], "retailer").AsString(); got != "Amazon" {
+		t.Errorf("Searcher.Search retailer = %q", got)
+	}
+	if attr(spans["cache.lookup"][0], "cache.hit").AsBool() {
+		t.Errorf("first lookup should miss the cache")
+	}
+
+	attempts := spans["HTTP POST"]
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 HTTP attempt spans, got %d", len(attempts))
+	}
+	if attr(attempts[0], "retry.attempt").AsInt64() != 1 || len(attempts[0].Events) == 0 {
+		t.Errorf("first attempt should record its error: %+v", attempts[0])
+	}
+	if attr(attempts[1], "retry.attempt").AsInt64() != 2 || attr(attempts[1], "http.response.status_code").AsInt64() != 200 {
+		t.Errorf("unexpected second attempt attributes %v", attempts[1].Attributes)
+	}
+	if traceparent == "" || traceparent[3:35] != root.SpanContext.TraceID().String() {
+		t.Errorf("trace context not propagated to Zinc, traceparent = %q", traceparent)
+	}
+}
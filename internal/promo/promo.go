@@ -0,0 +1,102 @@
+// Package promo works out what offers cost a particular shopper once the
+// coupons, subscription discounts and member prices they qualify for are
+// applied.
+package promo
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// Profile declares what a shopper qualifies for.
+type Profile struct {
+	Memberships []domain.Membership
+	// SubscribeSave means the shopper is willing to order on a recurring
+	// subscription to get its discount.
+	SubscribeSave bool
+}
+
+// ParseMembership accepts prime, walmart_plus, walmart-plus or walmart+,
+// ignoring case.
+func ParseMembership(s string) (domain.Membership, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "prime", "amazon-prime", "amazon_prime":
+		return domain.MembershipPrime, nil
+	case "walmart_plus", "walmart-plus", "walmart+", "walmartplus":
+		return domain.MembershipWalmartPlus, nil
+	}
+	return "", fmt.Errorf("invalid membership %q: want prime or walmart_plus", s)
+}
+
+// NewProfile creates a Profile from membership names, as accepted by
+// ParseMembership.
+func NewProfile(memberships []string, subscribeSave bool) (Profile, error) {
+	p := Profile{SubscribeSave: subscribeSave}
+	for _, name := range memberships {
+		m, err := ParseMembership(name)
+		if err != nil {
+			return Profile{}, err
+		}
+		if !slices.Contains(p.Memberships, m) {
+			p.Memberships = append(p.Memberships, m)
+		}
+	}
+	return p, nil
+}
+
+// IsZero reports whether the profile declares nothing.
+func (p Profile) IsZero() bool {
+	return len(p.Memberships) == 0 && !p.SubscribeSave
+}
+
+// applies reports whether promo is available to the shopper. Coupons are
+// available to everyone.
+func (p Profile) applies(promo domain.Promotion) bool {
+	switch promo.Kind {
+	case domain.PromotionCoupon:
+		return true
+	case domain.PromotionSubscribeSave:
+		return p.SubscribeSave
+	case domain.PromotionMember:
+		return slices.Contains(p.Memberships, promo.Membership)
+	}
+	return false
+}
+
+// Savings returns what the promotions available to the shopper take off
+// o.Price, rounded to the cent. Member prices apply first, then amounts off,
+// then percentages off the reduced price, and the price never drops below
+// zero.
+func (p Profile) Savings(o domain.Offer) float64 {
+	var amounts, percents float64
+	price := o.Price
+	for _, promo := range o.Promotions {
+		if !p.applies(promo) {
+			continue
+		}
+		switch {
+		case promo.Kind == domain.PromotionMember:
+			price -= promo.AmountOff
+		case promo.AmountOff > 0:
+			amounts += promo.AmountOff
+		default:
+			percents += promo.PercentOff
+		}
+	}
+	price = (price - amounts) * (1 - min(percents, 100)/100)
+	return math.Round((o.Price-max(price, 0))*100) / 100
+}
+
+// Apply returns a copy of offers with Savings set for the shopper.
+func (p Profile) Apply(offers []domain.Offer) []domain.Offer {
+	out := make([]domain.Offer, len(offers))
+	for i, o := range offers {
+		o.Savings = p.Savings(o)
+		out[i] = o
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package promo
+
+import (
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestProfile_Savings(t *testing.T) {
+	offer := domain.Offer{
+		Price:     50,
+		ListPrice: 60,
+		Retailer:  domain.Amazon,
+		Promotions: []domain.Promotion{
+			{Kind: domain.PromotionCoupon, AmountOff: 5},
+			{Kind: domain.PromotionSubscribeSave, PercentOff: 10},
+			{Kind: domain.PromotionMember, Membership: domain.MembershipPrime, AmountOff: 3},
+		},
+	}
+	tests := []struct {
+		name    string
+		profile Profile
+		want    float64
+	}{
+		{"coupon only", Profile{}, 5},
+		{"subscribe and save", Profile{SubscribeSave: true}, 9.5},
+		{"prime member", Profile{Memberships: []domain.Membership{domain.MembershipPrime}}, 8},
+		{"walmart+ member", Profile{Memberships: []domain.Membership{domain.MembershipWalmartPlus}}, 5},
+		{"everything", Profile{Memberships: []domain.Membership{domain.MembershipPrime}, SubscribeSave: true}, 12.2},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.Savings(offer); got != tt.want {
+			t.Errorf("%s: Savings() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	offer.Savings = 12.2
+	if got := offer.EffectivePrice(); got != 37.8 {
+		t.Errorf("EffectivePrice() = %v, want 37.8", got)
+	}
+	if got := offer.Discount(); got != 0.37 {
+		t.Errorf("Discount() = %v, want 0.37 off the list price", got)
+	}
+}
+
+func TestProfile_SavingsNeverExceedPrice(t *testing.T) {
+	offer := domain.Offer{Price: 4, Promotions: []domain.Promotion{{Kind: domain.PromotionCoupon, AmountOff: 5}}}
+	if got := (Profile{}).Savings(offer); got != 4 {
+		t.Errorf("Savings() = %v, want the whole price", got)
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	p, err := NewProfile([]string{"Prime", "walmart+", "prime"}, false)
+	if err != nil {
+		t.Fatalf("NewProfile() error = %v", err)
+	}
+	if len(p.Memberships) != 2 || p.Memberships[0] != domain.MembershipPrime || p.Memberships[1] != domain.MembershipWalmartPlus {
+		t.Errorf("Memberships = %v", p.Memberships)
+	}
+	if _, err := NewProfile([]string{"costco"}, false); err == nil {
+		t.Error("NewProfile(costco) error = nil, want error")
+	}
+}
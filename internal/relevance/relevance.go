@@ -0,0 +1,168 @@
+// Package relevance rates offers against the search query so accessories and
+// off-target listings can be dropped.
+package relevance
+
+import (
+	"sort"
+	"strings"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/match"
+)
+
+const (
+	// accessoryPenalty scales the score of accessory listings when the query
+	// itself is not asking for an accessory.
+	accessoryPenalty = 0.3
+	// outlierPenalty scales the score of offers priced far below their peers.
+	outlierPenalty = 0.5
+	// outlierRatio is the fraction of the median price below which an offer
+	// is treated as an outlier.
+	outlierRatio = 0.35
+	// minClusterSize is the smallest product cluster whose median is trusted;
+	// smaller clusters fall back to the median of the whole result set.
+	minClusterSize = 3
+)
+
+// Scorer rates offers against a query.
+type Scorer struct {
+	// Exclude lists keywords that drop an offer when they appear in its
+	// title, case-insensitively, whatever the relevance threshold.
+	Exclude []string
+}
+
+// Score returns the offers not matching Exclude, with Relevance set.
+func (s Scorer) Score(query string, offers []domain.Offer) []domain.Offer {
+	kept := make([]domain.Offer, 0, len(offers))
+	for _, o := range offers {
+		if !s.excluded(o.Title) {
+			kept = append(kept, o)
+		}
+	}
+	offers = kept
+	queryTokens := match.Tokens(query)
+	accessoryQuery := match.IsAccessory(query)
+	medians := clusterMedians(offers)
+
+	for i := range offers {
+		o := &offers[i]
+		score := overlap(queryTokens, match.Tokens(o.Title))
+		if !accessoryQuery && match.IsAccessory(o.Title) {
+			score *= accessoryPenalty
+		}
+		if median := medians[i]; median > 0 && o.Price < median*outlierRatio {
+			score *= outlierPenalty
+		}
+		o.Relevance = score
+	}
+	return offers
+}
+
+// Filter returns the offers whose Relevance is at least min.
+func Filter(offers []domain.Offer, min float64) []domain.Offer {
+	var kept []domain.Offer
+	for _, o := range offers {
+		if o.Relevance >= min {
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
+
+func (s Scorer) excluded(title string) bool {
+	title = strings.ToLower(title)
+	for _, kw := range s.Exclude {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		if kw != "" && strings.Contains(title, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlap returns the fraction of query tokens that appear in the title.
+func overlap(query, title []string) float64 {
+	if len(query) == 0 {
+		return 1
+	}
+	have := make(map[string]bool, len(title))
+	for _, t := range title {
+		have[t] = true
+	}
+	found := 0
+	for _, q := range query {
+		if have[q] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(query))
+}
+
+// clusterMedians returns, for each offer, the median price of its product
+// cluster, or of the whole result set when the cluster is too small to say.
+// Prices in different currencies cannot be compared, so the medians are
+// taken among the offers in the same currency.
+func clusterMedians(offers []domain.Offer) []float64 {
+	byCurrency := make(map[string][]int)
+	for i, o := range offers {
+		byCurrency[o.Currency()] = append(byCurrency[o.Currency()], i)
+	}
+	medians := make([]float64, len(offers))
+	for _, indexes := range byCurrency {
+		same := make([]domain.Offer, len(indexes))
+		for j, i := range indexes {
+			same[j] = offers[i]
+		}
+		for j, m := range currencyMedians(same) {
+			medians[indexes[j]] = m
+		}
+	}
+	return medians
+}
+
+// currencyMedians is clusterMedians for offers all in one currency.
+func currencyMedians(offers []domain.Offer) []float64 {
+	all := make([]float64, len(offers))
+	for i, o := range offers {
+		all[i] = o.Price
+	}
+	overall := median(all)
+
+	byTitle := make(map[string]float64)
+	for _, c := range match.Group(offers) {
+		if len(c.Offers) < minClusterSize {
+			continue
+		}
+		prices := make([]float64, len(c.Offers))
+		for i, o := range c.Offers {
+			prices[i] = o.Price
+		}
+		m := median(prices)
+		for _, o := range c.Offers {
+			byTitle[o.Title] = m
+		}
+	}
+
+	medians := make([]float64, len(offers))
+	for i, o := range offers {
+		if m, ok := byTitle[o.Title]; ok {
+			medians[i] = m
+		} else {
+			medians[i] = overall
+		}
+	}
+	return medians
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
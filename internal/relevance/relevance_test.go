@@ -0,0 +1,79 @@
+package relevance
+
+import (
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestScorer_Score(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "Apple AirPods Pro (2nd Generation)", Price: 199.99},
+		{Title: "Silicone Case for AirPods Pro 2", Price: 12.99},
+		{Title: "USB-C Cable 6ft", Price: 7.99},
+		{Title: "AirPods Pro 2 Replacement Ear Tips", Price: 189.00},
+	}
+
+	scored := Scorer{}.Score("AirPods Pro 2", offers)
+
+	if got := scored[0].Relevance; got != 1 {
+		t.Errorf("exact product relevance = %v, want 1", got)
+	}
+	if got := scored[1].Relevance; got >= 0.3 {
+		t.Errorf("accessory relevance = %v, want < 0.3", got)
+	}
+	if got := scored[2].Relevance; got != 0 {
+		t.Errorf("unrelated relevance = %v, want 0", got)
+	}
+	if got := scored[3].Relevance; got >= 0.5 {
+		t.Errorf("replacement part relevance = %v, want < 0.5", got)
+	}
+}
+
+func TestScorer_PriceOutlier(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "Nintendo Switch OLED", Price: 349.99},
+		{Title: "Nintendo Switch OLED Console", Price: 339.00},
+		{Title: "Nintendo Switch OLED Model", Price: 329.99},
+		{Title: "Nintendo Switch OLED", Price: 24.99},
+	}
+
+	scored := Scorer{}.Score("nintendo switch oled", offers)
+	if got := scored[0].Relevance; got != 1 {
+		t.Errorf("fairly priced relevance = %v, want 1", got)
+	}
+	if got := scored[3].Relevance; got != outlierPenalty {
+		t.Errorf("outlier relevance = %v, want %v", got, outlierPenalty)
+	}
+}
+
+func TestScorer_MixedCurrencies(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "Sony WH-1000XM5", Price: 348, Retailer: domain.Amazon},
+		{Title: "Sony WH-1000XM5", Price: 329, Retailer: domain.Walmart},
+		{Title: "Sony WH-1000XM5", Price: 39800, Retailer: domain.AmazonJP},
+		{Title: "Sony WH-1000XM5", Price: 41000, Retailer: domain.AmazonJP},
+		{Title: "Sony WH-1000XM5", Price: 42000, Retailer: domain.AmazonJP},
+	}
+	for _, o := range (Scorer{}).Score("sony wh-1000xm5", offers) {
+		if o.Relevance != 1 {
+			t.Errorf("%s %v relevance = %v, want no outlier penalty across currencies", o.Retailer, o.Price, o.Relevance)
+		}
+	}
+}
+
+func TestScorer_Exclude(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro 2 (Renewed)", Price: 150},
+		{Title: "AirPods Pro 2", Price: 199},
+	}
+
+	scored := Scorer{Exclude: []string{"renewed"}}.Score("airpods pro 2", offers)
+	if len(scored) != 1 || scored[0].Title != "AirPods Pro 2" {
+		t.Errorf("Score() = %+v, want only the new listing", scored)
+	}
+	// Excluded offers are gone even with no relevance threshold.
+	if kept := Filter(scored, 0); len(kept) != 1 {
+		t.Errorf("Filter(0) = %+v, want only the new listing", kept)
+	}
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/match"
+)
+
+// Compare writes one row per product cluster with each retailer's best price
+// side by side, followed by the match confidence.
+func Compare(w io.Writer, clusters []match.Cluster) error {
+	retailers := clusterRetailers(clusters)
+
+	var g grid
+	header := []string{"Product"}
+	for _, r := range retailers {
+		header = append(header, string(r))
+	}
+	g.add(append(header, "Match")...)
+
+	for _, c := range clusters {
+		row := []string{truncateCells(c.Title, maxTitleWidth)}
+		for _, r := range retailers {
+			if best, ok := c.Best(r); ok {
+				row = append(row, formatPrice(best.EffectivePrice(), r))
+			} else {
+				row = append(row, "-")
+			}
+		}
+		g.add(append(row, fmt.Sprintf("%.0f%%", c.Confidence*100))...)
+	}
+	return g.write(w)
+}
+
+// clusterRetailers returns every retailer present in clusters, sorted by name.
+func clusterRetailers(clusters []match.Cluster) []domain.Retailer {
+	seen := make(map[domain.Retailer]bool)
+	var retailers []domain.Retailer
+	for _, c := range clusters {
+		for _, o := range c.Offers {
+			if !seen[o.Retailer] {
+				seen[o.Retailer] = true
+				retailers = append(retailers, o.Retailer)
+			}
+		}
+	}
+	sort.Slice(retailers, func(i, j int) bool { return retailers[i] < retailers[j] })
+	return retailers
+}
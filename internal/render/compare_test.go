@@ -0,0 +1,47 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/match"
+)
+
+func TestCompare_Golden(t *testing.T) {
+	clusters := []match.Cluster{
+		{
+			Title: "AirPods Pro (2nd Generation)",
+			Offers: []domain.Offer{
+				{Title: "AirPods Pro (2nd Generation)", Price: 199.99, Retailer: domain.Amazon},
+				{Title: "Apple AirPods Pro 2", Price: 189.00, Retailer: domain.Walmart},
+			},
+			Confidence: 0.75,
+			Basis:      match.ByTitle,
+		},
+		{
+			Title:      "Silicone Case for AirPods Pro",
+			Offers:     []domain.Offer{{Title: "Silicone Case for AirPods Pro", Price: 9.99, Retailer: domain.Amazon}},
+			Confidence: 1,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Compare(&buf, clusters); err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "compare.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("Compare() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/forecast"
+	"savvyshopper/internal/watch"
+)
+
+// dateFormat shows a day with its weekday, since prices may follow a weekly
+// pattern.
+const dateFormat = "Mon 2006-01-02"
+
+// Forecast writes a summary of the expected low over the forecast period,
+// followed by the expected price and its 95% band for each day.
+func Forecast(w io.Writer, title string, retailer domain.Retailer, f forecast.Forecast) error {
+	money := func(v float64) string { return formatPrice(v, retailer) }
+	fmt.Fprintf(w, "%s (%s)\n", title, retailer)
+	fmt.Fprintf(w, "Last price: %s on %s, from %d days of history\n", money(f.Last.Price), f.Last.Date.Format(dateFormat), f.History)
+	fmt.Fprintf(w, "Expected low over the next %d days: %s on %s (95%% range %s-%s)\n",
+		len(f.Days), money(f.Low.Price), f.Low.Date.Format(dateFormat), money(f.Low.Low), money(f.Low.High))
+	if f.Seasonal {
+		fmt.Fprintf(w, "Prices tend to be lowest on %ss.\n", f.Weekday)
+	}
+	fmt.Fprintln(w)
+
+	var g grid
+	g.add("Date", "Expected", "Low", "High")
+	for _, d := range f.Days {
+		g.add(d.Date.Format(dateFormat), money(d.Price), money(d.Low), money(d.High))
+	}
+	return g.write(w)
+}
+
+// Watches writes the watch list with each listing's last recorded price,
+// keyed by watch ID; a missing price shows as a dash.
+func Watches(w io.Writer, watches []watch.Watch, prices map[string]float64) error {
+	var g grid
+	g.add("ID", "Retailer", "Product", "Last Price", "Title")
+	for _, wt := range watches {
+		last := "-"
+		if p, ok := prices[wt.ID]; ok {
+			last = formatPrice(p, wt.Retailer)
+		}
+		g.add(wt.ID, string(wt.Retailer), wt.ProductID, last, truncateCells(wt.Title, maxTitleWidth))
+	}
+	return g.write(w)
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/forecast"
+	"savvyshopper/internal/watch"
+)
+
+func TestForecast_Golden(t *testing.T) {
+	day := func(d int, price, low, high float64) forecast.Day {
+		return forecast.Day{Date: time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC), Price: price, Low: low, High: high}
+	}
+	f := forecast.Forecast{
+		Days:     []forecast.Day{day(19, 189.5, 180.25, 198.75), day(20, 179.4, 168.9, 189.9), day(21, 188.8, 176.1, 201.5)},
+		Low:      day(20, 179.4, 168.9, 189.9),
+		Last:     day(18, 189.99, 189.99, 189.99),
+		History:  42,
+		Seasonal: true,
+		Weekday:  time.Tuesday,
+	}
+
+	var buf bytes.Buffer
+	if err := Forecast(&buf, "Apple AirPods Pro 2", domain.Amazon, f); err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "forecast.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("Forecast() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestWatches(t *testing.T) {
+	watches := []watch.Watch{
+		{ID: "1", Retailer: domain.Amazon, ProductID: "B0D1XD1ZV3", Title: "Apple AirPods Pro 2"},
+		{ID: "2", Retailer: domain.AmazonUK, ProductID: "B0D1XD1ZV3"},
+	}
+	var buf bytes.Buffer
+	if err := Watches(&buf, watches, map[string]float64{"1": 189.99}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "$189.99") || !strings.Contains(lines[2], "-") {
+		t.Errorf("Watches() = %q", buf.String())
+	}
+}
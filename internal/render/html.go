@@ -0,0 +1,100 @@
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+//go:embed templates/offers.html
+var offersHTML string
+
+var offersTemplate = template.Must(template.New("offers").Parse(offersHTML))
+
+// HTMLOptions controls an HTML page of offers.
+type HTMLOptions struct {
+	TableOptions
+	// Title heads the page; "Prices" if empty.
+	Title string
+	// Template renders the page instead of the built-in one. See
+	// LoadHTMLTemplate.
+	Template *template.Template
+}
+
+// HTMLPage is what the HTML template is executed with.
+type HTMLPage struct {
+	Title string
+	// Columns names the optional columns, such as "Deal", after Retailer.
+	Columns []string
+	Offers  []HTMLOffer
+}
+
+// HTMLOffer is one offer's row on an HTMLPage.
+type HTMLOffer struct {
+	Title    string
+	URL      string
+	Retailer domain.Retailer
+	// Brand is the retailer's company in lower case, amazon or walmart, for
+	// styling its badge.
+	Brand string
+	// Price is the effective price, formatted for the offer's marketplace.
+	Price string
+	// Best is set on the cheapest offers in each currency.
+	Best bool
+	// Cells are the offer's values for Columns.
+	Cells []string
+}
+
+// LoadHTMLTemplate reads the definitions in the template file at path on top
+// of the built-in template. The file can redefine any of its blocks: "title",
+// "style", "header" and "footer" for branding, or "offers" for the whole
+// page. Anything outside a define is ignored.
+func LoadHTMLTemplate(path string) (*template.Template, error) {
+	t, err := template.Must(template.New("offers").Parse(offersHTML)).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load HTML template: %w", err)
+	}
+	return t.Lookup("offers"), nil
+}
+
+// HTML writes the offers as a standalone HTML page, for email. Titles link to
+// the offers, retailers are shown as badges and the cheapest price in each
+// currency is highlighted. The columns are Table's, less the URL.
+func HTML(w io.Writer, offers []domain.Offer, optsOpt ...HTMLOptions) error {
+	var opts HTMLOptions
+	if len(optsOpt) > 0 {
+		opts = optsOpt[0]
+	}
+	if !opts.Discounts {
+		opts.Discounts = slices.ContainsFunc(offers, discounted)
+	}
+	opts.URLs = URLNone
+	t := opts.Template
+	if t == nil {
+		t = offersTemplate
+	}
+
+	header := opts.header()
+	page := HTMLPage{Title: opts.Title, Columns: header[3:], Offers: make([]HTMLOffer, len(offers))}
+	if page.Title == "" {
+		page.Title = "Prices"
+	}
+	best := bestPrices(offers)
+	for i, o := range offers {
+		page.Offers[i] = HTMLOffer{
+			Title:    o.Title,
+			URL:      o.URL,
+			Retailer: o.Retailer,
+			Brand:    strings.ToLower(string(o.Retailer.Marketplace().Brand)),
+			Price:    formatPrice(o.EffectivePrice(), o.Retailer),
+			Best:     best.is(o),
+			Cells:    opts.row(o),
+		}
+	}
+	return t.Execute(w, page)
+}
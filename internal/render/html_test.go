@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestHTML(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods <Pro>", Price: 189.99, Retailer: domain.Amazon, URL: "https://www.amazon.com/dp/B0D1XD1ZV3"},
+		{Title: "AirPods Pro", Price: 179, Retailer: domain.Walmart, URL: "https://www.walmart.com/ip/5689919121", Deal: domain.Deal{Score: 80, Advice: domain.AdviceBuy, Reason: "at its 90-day low"}},
+	}
+	var buf bytes.Buffer
+	if err := HTML(&buf, offers, HTMLOptions{TableOptions: TableOptions{Deals: true}, Title: "Prices for AirPods"}); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Prices for AirPods</title>",
+		`<a href="https://www.amazon.com/dp/B0D1XD1ZV3">AirPods &lt;Pro&gt;</a>`,
+		`<span class="badge walmart">Walmart</span>`,
+		`<td class="price best">$179.00</td>`,
+		`<td class="price">$189.99</td>`,
+		"<th>Deal</th><th>Advice</th></tr>",
+		"<td>buy now: at its 90-day low</td>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLoadHTMLTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brand.html")
+	brand := `{{define "header"}}<h1 class="acme">Acme deals: {{.Title}}</h1>{{end}}` +
+		`{{define "footer"}}<p>{{len .Offers}} offers</p>{{end}}`
+	if err := os.WriteFile(path, []byte(brand), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadHTMLTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadHTMLTemplate() error = %v", err)
+	}
+	offers := []domain.Offer{{Title: "AirPods", Price: 179, Retailer: domain.Amazon}}
+	var buf bytes.Buffer
+	if err := HTML(&buf, offers, HTMLOptions{Title: "AirPods", Template: tmpl}); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`<h1 class="acme">Acme deals: AirPods</h1>`, "<p>1 offers</p>", `<span class="badge amazon">`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML() missing %q:\n%s", want, out)
+		}
+	}
+
+	// The built-in template is unchanged for everyone else.
+	buf.Reset()
+	if err := HTML(&buf, offers); err != nil || strings.Contains(buf.String(), "Acme") {
+		t.Errorf("HTML() after loading a template = %v:\n%s", err, buf.String())
+	}
+
+	if _, err := LoadHTMLTemplate(filepath.Join(t.TempDir(), "missing.html")); err == nil {
+		t.Error("LoadHTMLTemplate(missing) error = nil")
+	}
+}
@@ -0,0 +1,140 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/forecast"
+)
+
+// OfferJSON is the JSON shape of an offer shared by every machine-readable
+// output, so field names stay the same wherever an offer is printed or served.
+type OfferJSON struct {
+	Title         string    `json:"title"`
+	Price         float64   `json:"price"`
+	Currency      string    `json:"currency"`
+	ListPrice     float64   `json:"list_price,omitempty"`
+	Savings       float64   `json:"savings,omitempty"`
+	Effective     float64   `json:"effective_price"`
+	URL           string    `json:"url"`
+	Retailer      string    `json:"retailer"`
+	ProductID     string    `json:"product_id,omitempty"`
+	Seller        string    `json:"seller,omitempty"`
+	SellerRating  float64   `json:"seller_rating,omitempty"`
+	SellerRatings int       `json:"seller_ratings,omitempty"`
+	Fulfillment   string    `json:"fulfillment,omitempty"`
+	GTIN          string    `json:"gtin,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	Shipping      float64   `json:"shipping,omitempty"`
+	Tax           float64   `json:"tax,omitempty"`
+	Landed        float64   `json:"landed"`
+	Rating        float64   `json:"rating,omitempty"`
+	Reviews       int       `json:"reviews,omitempty"`
+	Condition     string    `json:"condition,omitempty"`
+	Prime         bool      `json:"prime,omitempty"`
+	OutOfStock    bool      `json:"out_of_stock,omitempty"`
+	Relevance     float64   `json:"relevance"`
+	Deal          *DealJSON `json:"deal,omitempty"`
+}
+
+// DealJSON is the JSON shape of a deal rating.
+type DealJSON struct {
+	Score  int    `json:"score"`
+	Advice string `json:"advice"`
+	Reason string `json:"reason"`
+}
+
+// NewOfferJSON converts an offer to its JSON shape. The deal is left out
+// until the offer is scored.
+func NewOfferJSON(o domain.Offer) OfferJSON {
+	var deal *DealJSON
+	if o.Deal.Advice != "" {
+		deal = &DealJSON{Score: o.Deal.Score, Advice: string(o.Deal.Advice), Reason: o.Deal.Reason}
+	}
+	return OfferJSON{
+		Title:         o.Title,
+		Price:         o.Price,
+		Currency:      o.Currency(),
+		ListPrice:     o.ListPrice,
+		Savings:       o.Savings,
+		Effective:     o.EffectivePrice(),
+		URL:           o.URL,
+		Retailer:      string(o.Retailer),
+		ProductID:     o.ProductID,
+		Seller:        o.Seller,
+		SellerRating:  o.SellerRating,
+		SellerRatings: o.SellerRatings,
+		Fulfillment:   string(o.Fulfillment),
+		GTIN:          o.GTIN,
+		Model:         o.Model,
+		Shipping:      o.Shipping,
+		Tax:           o.Tax,
+		Landed:        o.Landed(),
+		Rating:        o.Rating,
+		Reviews:       o.Reviews,
+		Condition:     string(o.Condition),
+		Prime:         o.Prime,
+		OutOfStock:    o.OutOfStock,
+		Relevance:     o.Relevance,
+		Deal:          deal,
+	}
+}
+
+// OffersJSON converts offers to their JSON shape. It never returns nil, so an
+// empty list encodes as [] rather than null.
+func OffersJSON(offers []domain.Offer) []OfferJSON {
+	out := make([]OfferJSON, len(offers))
+	for i, o := range offers {
+		out[i] = NewOfferJSON(o)
+	}
+	return out
+}
+
+// JSON writes the offers to w as an indented JSON array.
+func JSON(w io.Writer, offers []domain.Offer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(OffersJSON(offers))
+}
+
+// ForecastJSON is the JSON shape of a price forecast.
+type ForecastJSON struct {
+	Last    ForecastDayJSON   `json:"last"`
+	History int               `json:"history_days"`
+	Low     ForecastDayJSON   `json:"low"`
+	Days    []ForecastDayJSON `json:"days"`
+	// Weekday is the day of the week prices tend to be lowest, if they
+	// follow a weekly pattern.
+	Weekday string `json:"cheapest_weekday,omitempty"`
+}
+
+// ForecastDayJSON is the JSON shape of one forecast day. Dates are in
+// YYYY-MM-DD form.
+type ForecastDayJSON struct {
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+}
+
+// NewForecastJSON converts a forecast to its JSON shape.
+func NewForecastJSON(f forecast.Forecast) ForecastJSON {
+	day := func(d forecast.Day) ForecastDayJSON {
+		return ForecastDayJSON{Date: d.Date.Format("2006-01-02"), Price: round2(d.Price), Low: round2(d.Low), High: round2(d.High)}
+	}
+	out := ForecastJSON{Last: day(f.Last), History: f.History, Low: day(f.Low), Days: make([]ForecastDayJSON, len(f.Days))}
+	for i, d := range f.Days {
+		out.Days[i] = day(d)
+	}
+	if f.Seasonal {
+		out.Weekday = f.Weekday.String()
+	}
+	return out
+}
+
+// round2 rounds an amount to the cent.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestJSON(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro", Price: 199.99, Shipping: 5, Retailer: domain.Amazon, URL: "https://example.com/1", Prime: true},
+	}
+
+	var buf bytes.Buffer
+	if err := JSON(&buf, offers); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 offer, got %d", len(got))
+	}
+	if got[0]["title"] != "AirPods Pro" || got[0]["retailer"] != "Amazon" || got[0]["landed"] != 204.99 || got[0]["prime"] != true {
+		t.Errorf("unexpected JSON offer %v", got[0])
+	}
+	if _, ok := got[0]["seller"]; ok {
+		t.Errorf("empty seller should be omitted: %v", got[0])
+	}
+
+	buf.Reset()
+	if err := JSON(&buf, nil); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	if got := bytes.TrimSpace(buf.Bytes()); string(got) != "[]" {
+		t.Errorf("JSON(nil) = %s, want []", got)
+	}
+}
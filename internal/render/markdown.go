@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// Markdown writes the offers as a GitHub-flavored Markdown table, for pasting
+// into chat and pull requests. Titles link to the offers, retailers are shown
+// as code-span badges and the cheapest price in each currency is bold. The
+// columns are Table's, less the URL.
+func Markdown(w io.Writer, offers []domain.Offer, optsOpt ...TableOptions) error {
+	var opts TableOptions
+	if len(optsOpt) > 0 {
+		opts = optsOpt[0]
+	}
+	if !opts.Discounts {
+		opts.Discounts = slices.ContainsFunc(offers, discounted)
+	}
+	opts.URLs = URLNone
+
+	header := opts.header()
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	best := bestPrices(offers)
+	for _, offer := range offers {
+		title := markdownEscape(offer.Title)
+		if offer.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, strings.ReplaceAll(offer.URL, ")", "%29"))
+		}
+		price := formatPrice(offer.EffectivePrice(), offer.Retailer)
+		if best.is(offer) {
+			price = "**" + price + "**"
+		}
+		cols := []string{title, price, "`" + string(offer.Retailer) + "`"}
+		for _, c := range opts.row(offer) {
+			cols = append(cols, markdownEscape(c))
+		}
+		b.WriteString("| " + strings.Join(cols, " | ") + " |\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownReplacer escapes the characters that would end a table cell or
+// start a link or emphasis.
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, `|`, `\|`, `[`, `\[`, `]`, `\]`, `*`, `\*`, `_`, `\_`, "`", "\\`", "\n", " ",
+)
+
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// cheapest holds the lowest effective price in each currency, so offers on
+// different marketplaces are not compared.
+type cheapest map[string]float64
+
+// bestPrices finds the lowest effective price in each currency among offers.
+func bestPrices(offers []domain.Offer) cheapest {
+	best := make(cheapest)
+	for _, o := range offers {
+		currency := o.Retailer.Marketplace().Currency
+		if p, ok := best[currency]; !ok || o.EffectivePrice() < p {
+			best[currency] = o.EffectivePrice()
+		}
+	}
+	return best
+}
+
+// is reports whether o has the lowest price in its currency. Ties are all
+// the best.
+func (c cheapest) is(o domain.Offer) bool {
+	p, ok := c[o.Retailer.Marketplace().Currency]
+	return ok && o.EffectivePrice() == p
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestMarkdown_Golden(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "Apple AirPods Pro 2 | USB-C [2024]", Price: 189.99, Retailer: domain.Amazon, URL: "https://www.amazon.com/dp/B0D1XD1ZV3"},
+		{Title: "Apple AirPods Pro 2", Price: 199, ListPrice: 249, Retailer: domain.Walmart, URL: "https://www.walmart.com/ip/5689919121"},
+		{Title: "Apple AirPods Pro 2", Price: 179, Retailer: domain.AmazonUK},
+	}
+
+	var buf bytes.Buffer
+	if err := Markdown(&buf, offers); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "markdown.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("Markdown() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,67 @@
+package render
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// moneyFormat is how a locale writes an amount of its currency.
+type moneyFormat struct {
+	symbol   string
+	suffix   bool // symbol after the amount, separated by a space
+	decimals int
+	group    string
+	decimal  string
+}
+
+// moneyFormats is keyed by marketplace locale.
+var moneyFormats = map[string]moneyFormat{
+	"en-US": {symbol: "$", decimals: 2, group: ",", decimal: "."},
+	"en-GB": {symbol: "£", decimals: 2, group: ",", decimal: "."},
+	"de-DE": {symbol: "€", suffix: true, decimals: 2, group: ".", decimal: ","},
+	"ja-JP": {symbol: "¥", decimals: 0, group: ",", decimal: "."},
+	"en-CA": {symbol: "$", decimals: 2, group: ",", decimal: "."},
+}
+
+// Money formats amount in the currency of retailer's marketplace, as the
+// tables do.
+func Money(amount float64, retailer domain.Retailer) string {
+	return formatPrice(amount, retailer)
+}
+
+// formatPrice writes amount the way buyers on retailer's marketplace expect,
+// e.g. $1,299.00 on amazon.com, 1.299,00 € on amazon.de and ¥1,299 on
+// amazon.co.jp.
+func formatPrice(amount float64, retailer domain.Retailer) string {
+	f, ok := moneyFormats[retailer.Marketplace().Locale]
+	if !ok {
+		f = moneyFormats["en-US"]
+	}
+	s := strconv.FormatFloat(math.Abs(amount), 'f', f.decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if amount < 0 {
+		b.WriteByte('-')
+	}
+	if !f.suffix {
+		b.WriteString(f.symbol)
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(d)
+	}
+	if frac != "" {
+		b.WriteString(f.decimal)
+		b.WriteString(frac)
+	}
+	if f.suffix {
+		b.WriteString(" " + f.symbol)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		retailer domain.Retailer
+		want     string
+	}{
+		{199.99, domain.Amazon, "$199.99"},
+		{1299, domain.Walmart, "$1,299.00"},
+		{1234567.5, domain.Amazon, "$1,234,567.50"},
+		{199, domain.AmazonUK, "£199.00"},
+		{1899, domain.AmazonDE, "1.899,00 €"},
+		{22.5, domain.AmazonDE, "22,50 €"},
+		{39800, domain.AmazonJP, "¥39,800"},
+		{249.97, domain.WalmartCA, "$249.97"},
+		{0, domain.Amazon, "$0.00"},
+		{-5, domain.AmazonUK, "-£5.00"},
+		{10, domain.Retailer("Target"), "$10.00"},
+	}
+	for _, tt := range tests {
+		if got := formatPrice(tt.amount, tt.retailer); got != tt.want {
+			t.Errorf("formatPrice(%v, %s) = %q, want %q", tt.amount, tt.retailer, got, tt.want)
+		}
+	}
+}
+
+func TestTable_Marketplaces(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro 2", Price: 199, Retailer: domain.AmazonUK, URL: "https://www.amazon.co.uk/dp/B0D1XD1ZV3"},
+		{Title: "AirPods Pro 2", Price: 229, Retailer: domain.AmazonDE, URL: "https://www.amazon.de/dp/B0D1XD1ZV3"},
+	}
+	var buf bytes.Buffer
+	if err := Table(&buf, offers); err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	for _, want := range []string{"£199.00", "229,00 €", "Amazon UK", "Amazon DE"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Table() output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// maxDescription is the number of description characters shown before it is
+// cut short.
+const maxDescription = 280
+
+// Product writes a listing's details followed by every seller's offer.
+func Product(w io.Writer, p domain.ProductDetails) error {
+	var g grid
+	g.add("Title", p.Title)
+	g.add("Retailer", fmt.Sprintf("%s (%s)", p.Retailer, p.ProductID))
+	if p.Brand != "" {
+		g.add("Brand", p.Brand)
+	}
+	g.add("Price", formatPrice(p.Price, p.Retailer))
+	if p.OutOfStock {
+		g.add("Availability", "Out of stock")
+	} else {
+		g.add("Availability", "In stock")
+	}
+	if p.Rating > 0 {
+		g.add("Rating", fmt.Sprintf("%.1f (%d reviews)", p.Rating, p.Reviews))
+	}
+	g.add("Images", strconv.Itoa(len(p.Images)))
+	if len(p.Variants) > 0 {
+		g.add("Variants", variantSummary(p.Variants))
+	}
+	if p.URL != "" {
+		g.add("URL", p.URL)
+	}
+	if err := g.write(w); err != nil {
+		return err
+	}
+
+	if desc := strings.Join(strings.Fields(p.Description), " "); desc != "" {
+		if runes := []rune(desc); len(runes) > maxDescription {
+			desc = string(runes[:maxDescription]) + "..."
+		}
+		fmt.Fprintf(w, "\n%s\n", desc)
+	}
+
+	if len(p.Offers) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\n%d offers:\n", len(p.Offers))
+	return sellerTable(w, p.Retailer, p.Offers)
+}
+
+// Sellers writes each product's seller offers grouped under a heading for the
+// product, in the order given.
+func Sellers(w io.Writer, products ...domain.ProductDetails) error {
+	for i, p := range products {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n%s %s - %d offers\n", p.Title, p.Retailer, p.ProductID, len(p.Offers))
+		if len(p.Offers) == 0 {
+			continue
+		}
+		if err := sellerTable(w, p.Retailer, p.Offers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sellerTable writes one row per seller offer.
+func sellerTable(w io.Writer, retailer domain.Retailer, offers []domain.Offer) error {
+	var g grid
+	g.add("Seller", "Feedback", "Price", "Shipping", "Condition", "Fulfilled By", "Prime")
+	for _, o := range offers {
+		feedback := "-"
+		if o.SellerRatings > 0 {
+			feedback = fmt.Sprintf("%.0f%% (%d)", o.SellerRating, o.SellerRatings)
+		}
+		condition := string(o.Condition)
+		if condition == "" {
+			condition = string(domain.ConditionNew)
+		}
+		fulfilledBy := ""
+		switch o.Fulfillment {
+		case domain.FulfilledByRetailer:
+			fulfilledBy = string(retailer)
+		case domain.FulfilledBySeller:
+			fulfilledBy = "Seller"
+		}
+		prime := ""
+		if o.Prime {
+			prime = "yes"
+		}
+		g.add(o.Seller, feedback, formatPrice(o.Price, retailer), formatPrice(o.Shipping, retailer), condition, fulfilledBy, prime)
+	}
+	return g.write(w)
+}
+
+// variantSummary lists the variant values per dimension, e.g.
+// "Color: Black, White; Size: S, M".
+func variantSummary(variants []domain.Variant) string {
+	values := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, v := range variants {
+		for dim, val := range v.Specifics {
+			if key := dim + "\x00" + val; !seen[key] {
+				seen[key] = true
+				values[dim] = append(values[dim], val)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Sprintf("%d", len(variants))
+	}
+	dims := make([]string, 0, len(values))
+	for dim := range values {
+		dims = append(dims, dim)
+	}
+	sort.Strings(dims)
+	parts := make([]string, len(dims))
+	for i, dim := range dims {
+		parts[i] = dim + ": " + strings.Join(values[dim], ", ")
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,85 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestProduct_Golden(t *testing.T) {
+	details := domain.ProductDetails{
+		Retailer:    domain.Amazon,
+		ProductID:   "B0BDHWDR12",
+		Title:       "Apple AirPods Pro (2nd Generation)",
+		Description: "Active Noise Cancellation  reduces unwanted\nbackground noise.",
+		URL:         "https://www.amazon.com/dp/B0BDHWDR12",
+		Price:       199.99,
+		Rating:      4.7,
+		Reviews:     80512,
+		Images:      []string{"a.jpg", "b.jpg"},
+		Variants: []domain.Variant{
+			{ProductID: "B0BDHWDR12", Specifics: map[string]string{"Style": "Lightning"}},
+			{ProductID: "B0CHWRXH8B", Specifics: map[string]string{"Style": "USB-C"}},
+		},
+		Offers: []domain.Offer{
+			{Seller: "Amazon.com", Price: 199.99, Prime: true, Fulfillment: domain.FulfilledByRetailer},
+			{Seller: "Gadget Outlet", Price: 174.50, Shipping: 3.99, Condition: domain.ConditionUsed,
+				SellerRating: 97, SellerRatings: 1520, Fulfillment: domain.FulfilledBySeller},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Product(&buf, details); err != nil {
+		t.Fatalf("Product() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "product.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("Product() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestSellers_Golden(t *testing.T) {
+	products := []domain.ProductDetails{
+		{
+			Retailer:  domain.Amazon,
+			ProductID: "B0BDHWDR12",
+			Title:     "Apple AirPods Pro (2nd Generation)",
+			Offers: []domain.Offer{
+				{Seller: "Amazon.com", Price: 199.99, Prime: true, Fulfillment: domain.FulfilledByRetailer},
+				{Seller: "Audio Deals", Price: 189.00, SellerRating: 92, SellerRatings: 311, Fulfillment: domain.FulfilledByRetailer, Prime: true},
+			},
+		},
+		{
+			Retailer:  domain.Walmart,
+			ProductID: "1745313236",
+			Title:     "Apple AirPods Pro 2",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Sellers(&buf, products...); err != nil {
+		t.Fatalf("Sellers() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "sellers.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("Sellers() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
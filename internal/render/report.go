@@ -0,0 +1,228 @@
+package render
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/report"
+)
+
+// ReportJSON is the JSON shape of a price history report. Its field names
+// follow OfferJSON.
+type ReportJSON struct {
+	From     string              `json:"from,omitempty"`
+	To       string              `json:"to,omitempty"`
+	Products []ReportProductJSON `json:"products"`
+}
+
+// ReportProductJSON is one product in a ReportJSON. Stats is left out when
+// its listings are in different currencies.
+type ReportProductJSON struct {
+	Title    string           `json:"title"`
+	Currency string           `json:"currency,omitempty"`
+	Stats    *ReportStatsJSON `json:"stats,omitempty"`
+	Lines    []ReportLineJSON `json:"lines"`
+}
+
+// ReportLineJSON is one listing's price history in a ReportJSON.
+type ReportLineJSON struct {
+	Retailer string           `json:"retailer"`
+	Key      string           `json:"key"`
+	Title    string           `json:"title"`
+	URL      string           `json:"url,omitempty"`
+	Currency string           `json:"currency"`
+	Stats    ReportStatsJSON  `json:"stats"`
+	Points   []PricePointJSON `json:"points"`
+}
+
+// ReportStatsJSON summarises a run of prices.
+type ReportStatsJSON struct {
+	First PricePointJSON `json:"first"`
+	Last  PricePointJSON `json:"last"`
+	Min   PricePointJSON `json:"min"`
+	Max   PricePointJSON `json:"max"`
+	Count int            `json:"count"`
+}
+
+// PricePointJSON is a price and when it was seen, in RFC 3339 form.
+type PricePointJSON struct {
+	Time  string  `json:"time"`
+	Price float64 `json:"price"`
+}
+
+// NewReportJSON converts a report to its JSON shape.
+func NewReportJSON(r report.Report) ReportJSON {
+	out := ReportJSON{From: reportDate(r.From), To: reportDate(r.To), Products: make([]ReportProductJSON, len(r.Products))}
+	for i, p := range r.Products {
+		product := ReportProductJSON{Title: p.Title, Currency: p.Currency, Lines: make([]ReportLineJSON, len(p.Lines))}
+		if p.Currency != "" {
+			stats := newStatsJSON(p.Stats)
+			product.Stats = &stats
+		}
+		for j, l := range p.Lines {
+			line := ReportLineJSON{
+				Retailer: string(l.Retailer),
+				Key:      l.Key,
+				Title:    l.Title,
+				URL:      l.URL,
+				Currency: l.Currency,
+				Stats:    newStatsJSON(l.Stats),
+				Points:   make([]PricePointJSON, len(l.Points)),
+			}
+			for k, pt := range l.Points {
+				line.Points[k] = newPricePointJSON(pt)
+			}
+			product.Lines[j] = line
+		}
+		out.Products[i] = product
+	}
+	return out
+}
+
+func newStatsJSON(s report.Stats) ReportStatsJSON {
+	return ReportStatsJSON{
+		First: newPricePointJSON(s.First),
+		Last:  newPricePointJSON(s.Last),
+		Min:   newPricePointJSON(s.Min),
+		Max:   newPricePointJSON(s.Max),
+		Count: s.Count,
+	}
+}
+
+func newPricePointJSON(p history.Point) PricePointJSON {
+	return PricePointJSON{Time: p.Time.UTC().Format(time.RFC3339), Price: p.Price}
+}
+
+// reportDate formats a report bound as YYYY-MM-DD, or empty for an open end.
+func reportDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// JSONReport writes the report to w as indented JSON.
+func JSONReport(w io.Writer, r report.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewReportJSON(r))
+}
+
+// reportCSVHeader names the CSV columns, following the JSON field names.
+var reportCSVHeader = []string{
+	"product", "retailer", "key", "title", "url", "currency",
+	"first_time", "first_price", "last_time", "last_price",
+	"min_time", "min_price", "max_time", "max_price", "count",
+}
+
+// CSVReport writes one row per listing, with its product's title first so
+// rows can be grouped in a spreadsheet. Prices are plain numbers in the
+// listing's currency.
+func CSVReport(w io.Writer, r report.Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportCSVHeader); err != nil {
+		return err
+	}
+	for _, p := range r.Products {
+		for _, l := range p.Lines {
+			row := []string{p.Title, string(l.Retailer), l.Key, l.Title, l.URL, l.Currency}
+			for _, pt := range []history.Point{l.Stats.First, l.Stats.Last, l.Stats.Min, l.Stats.Max} {
+				row = append(row, pt.Time.UTC().Format(time.RFC3339), strconv.FormatFloat(pt.Price, 'f', 2, 64))
+			}
+			row = append(row, strconv.Itoa(l.Stats.Count))
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+//go:embed templates/report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"money": func(amount float64, retailer domain.Retailer) string { return formatPrice(amount, retailer) },
+	"date":  func(p history.Point) string { return p.Time.UTC().Format("2006-01-02") },
+	"chart": chartSVG,
+	"color": func(i int) string { return chartColors[i%len(chartColors)] },
+}).Parse(reportHTML))
+
+// HTMLReport writes the report as a self-contained HTML page: per product, a
+// chart of each listing's price over time, as inline SVG, and a table of its
+// first, last, lowest and highest prices.
+func HTMLReport(w io.Writer, r report.Report) error {
+	return reportTemplate.Execute(w, struct {
+		From, To string
+		Products []report.Product
+	}{reportDate(r.From), reportDate(r.To), r.Products})
+}
+
+// Chart dimensions, in SVG user units.
+const (
+	chartWidth   = 640
+	chartHeight  = 220
+	chartPadLeft = 70
+	chartPad     = 20
+)
+
+// chartColors tells apart the listings on a chart.
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b"}
+
+// chartSVG draws each of the product's listings as a line of price against
+// time, on shared axes labelled with the price range and dates. Products
+// whose listings are in different currencies get no chart.
+func chartSVG(p report.Product) template.HTML {
+	if p.Currency == "" || p.Stats.Count == 0 {
+		return ""
+	}
+	t0, t1 := p.Stats.First.Time, p.Stats.Last.Time
+	lo, hi := p.Stats.Min.Price, p.Stats.Max.Price
+	if hi == lo {
+		lo, hi = lo*0.95, hi*1.05
+	}
+	span := t1.Sub(t0).Seconds()
+	plotW, plotH := float64(chartWidth-chartPadLeft-chartPad), float64(chartHeight-2*chartPad)
+	x := func(t time.Time) float64 {
+		if span == 0 {
+			return chartPadLeft + plotW/2
+		}
+		return chartPadLeft + plotW*t.Sub(t0).Seconds()/span
+	}
+	y := func(price float64) float64 {
+		return chartPad + plotH*(hi-price)/(hi-lo)
+	}
+
+	retailer := p.Lines[0].Retailer
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" role="img">`, chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.0f" height="%.0f" fill="none" stroke="#ccc"/>`, chartPadLeft, chartPad, plotW, plotH)
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" font-size="11">%s</text>`, chartPadLeft-6, y(hi)+4, template.HTMLEscapeString(formatPrice(hi, retailer)))
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" font-size="11">%s</text>`, chartPadLeft-6, y(lo)+4, template.HTMLEscapeString(formatPrice(lo, retailer)))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11">%s</text>`, chartPadLeft, chartHeight-4, t0.UTC().Format("2006-01-02"))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" font-size="11">%s</text>`, chartWidth-chartPad, chartHeight-4, t1.UTC().Format("2006-01-02"))
+	for i, l := range p.Lines {
+		color := chartColors[i%len(chartColors)]
+		points := make([]string, len(l.Points))
+		for j, pt := range l.Points {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(pt.Time), y(pt.Price))
+		}
+		if len(points) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, x(l.Points[0].Time), y(l.Points[0].Price), color)
+			continue
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), color)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/report"
+)
+
+func testReport() report.Report {
+	const airpods = "Apple AirPods Pro 2 Wireless Earbuds"
+	point := func(day int, retailer domain.Retailer, id string, price float64) history.Point {
+		return history.Point{
+			Time:     time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC),
+			Key:      history.ProductKey(retailer, id),
+			Retailer: retailer,
+			Title:    airpods,
+			URL:      "https://example.com/" + id,
+			Price:    price,
+		}
+	}
+	return report.Build(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), []history.Point{
+		point(1, domain.Amazon, "B0D1XD1ZV3", 199),
+		point(1, domain.Walmart, "5689919121", 189),
+		point(2, domain.Amazon, "B0D1XD1ZV3", 179),
+		point(3, domain.Amazon, "B0D1XD1ZV3", 209),
+	})
+}
+
+func TestCSVReport_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := CSVReport(&buf, testReport()); err != nil {
+		t.Fatalf("CSVReport() error = %v", err)
+	}
+
+	got := strings.TrimSpace(buf.String())
+	golden, err := os.ReadFile(filepath.Join("testdata", "report.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	expected := strings.TrimSpace(string(golden))
+
+	if got != expected {
+		t.Errorf("CSVReport() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSONReport(&buf, testReport()); err != nil {
+		t.Fatalf("JSONReport() error = %v", err)
+	}
+	var got ReportJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.From != "2026-10-01" || got.To != "2026-10-03" || len(got.Products) != 1 {
+		t.Fatalf("JSONReport() = %+v", got)
+	}
+	p := got.Products[0]
+	if p.Stats == nil || p.Stats.Min.Price != 179 || p.Stats.Min.Time != "2026-10-02T00:00:00Z" || p.Stats.Count != 4 {
+		t.Errorf("product stats = %+v", p.Stats)
+	}
+	if len(p.Lines) != 2 || p.Lines[0].Retailer != "Amazon" || len(p.Lines[0].Points) != 3 || p.Lines[1].Stats.Last.Price != 189 {
+		t.Errorf("product lines = %+v", p.Lines)
+	}
+	for _, field := range []string{`"retailer"`, `"key"`, `"currency"`, `"first"`, `"max"`, `"points"`} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("JSONReport() missing field %s", field)
+		}
+	}
+}
+
+func TestHTMLReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTMLReport(&buf, testReport()); err != nil {
+		t.Fatalf("HTMLReport() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<svg", "<polyline", "<circle", "$179.00", `href="https://example.com/B0D1XD1ZV3"`, "2026-10-01"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTMLReport() missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<script") || strings.Contains(out, "ZgotmplZ") {
+		t.Errorf("HTMLReport() is not self-contained or has unsafe values:\n%s", out)
+	}
+}
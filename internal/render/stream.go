@@ -0,0 +1,112 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// Fixed column widths for TableStream, in terminal columns; rows written at
+// different times must line up without seeing each other.
+const (
+	streamPriceWidth    = 9
+	streamRetailerWidth = 9
+	streamDealWidth     = 4
+)
+
+// TableStream writes offers as each retailer's results arrive, so a slow
+// retailer does not hold back a fast one.
+type TableStream struct {
+	w          io.Writer
+	opts       TableOptions
+	titleWidth int
+	headerDone bool
+}
+
+// NewTableStream creates a TableStream writing to w.
+func NewTableStream(w io.Writer, optsOpt ...TableOptions) *TableStream {
+	var opts TableOptions
+	if len(optsOpt) > 0 {
+		opts = optsOpt[0]
+	}
+	return &TableStream{w: w, opts: opts, titleWidth: streamTitleFor(opts)}
+}
+
+// streamTitleFor returns the title column width that leaves room for
+// minURLWidth of the URL, or maxTitleWidth if there is room or no terminal
+// width is set.
+func streamTitleFor(opts TableOptions) int {
+	if opts.Terminal.Width == 0 {
+		return maxTitleWidth
+	}
+	gap := len(columnGap)
+	others := gap + streamPriceWidth + gap + streamRetailerWidth + gap + minURLWidth
+	if opts.Discounts {
+		others += 2 * (gap + streamPriceWidth)
+	}
+	if opts.Landed {
+		others += 3 * (gap + streamPriceWidth)
+	}
+	if opts.Deals {
+		others += gap + streamDealWidth
+	}
+	return min(max(opts.Terminal.Width-others, minTitleWidth), maxTitleWidth)
+}
+
+// Offers writes a batch of offers, preceded by the header on first use.
+func (t *TableStream) Offers(offers []domain.Offer) error {
+	if !t.headerDone {
+		t.headerDone = true
+		if err := t.row(t.opts.header()...); err != nil {
+			return err
+		}
+	}
+	best := cheapestByRetailer(offers)
+	for _, offer := range offers {
+		if err := t.row(t.opts.cells(offer, best)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Failed notes that a retailer's search failed, in red on a color terminal.
+func (t *TableStream) Failed(retailer domain.Retailer, err error) error {
+	_, werr := fmt.Fprintln(t.w, t.opts.Terminal.Paint(Red, fmt.Sprintf("%s: %v", retailer, err)))
+	return werr
+}
+
+// row writes one line. The title, price and retailer columns, the amounts of
+// the discount and landed-cost columns, and the deal score have fixed widths.
+// Given a terminal width, the last column is cut short to fit it.
+func (t *TableStream) row(cols ...string) error {
+	line := padCells(truncateCells(cols[0], t.titleWidth), t.titleWidth) + columnGap + padCells(cols[1], streamPriceWidth) + columnGap + padCells(cols[2], streamRetailerWidth)
+	rest := cols[3:]
+	fixed := 0
+	if t.opts.Discounts {
+		fixed += 2
+	}
+	if t.opts.Landed {
+		fixed += 3
+	}
+	for _, col := range rest[:fixed] {
+		line += columnGap + padCells(col, streamPriceWidth)
+	}
+	rest = rest[fixed:]
+	if t.opts.Deals {
+		line += columnGap + padCells(rest[0], streamDealWidth)
+		rest = rest[1:]
+	}
+	for _, col := range rest {
+		line += columnGap + col
+	}
+	if width := t.opts.Terminal.Width; width > 0 && len(rest) > 0 && cellWidth(line) > width {
+		last := rest[len(rest)-1]
+		keep := max(cellWidth(last)-(cellWidth(line)-width), minURLWidth)
+		line = strings.TrimSuffix(line, last) + truncateCells(last, keep)
+	}
+	_, err := fmt.Fprintln(t.w, line)
+	return err
+}
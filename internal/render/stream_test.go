@@ -0,0 +1,71 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestTableStream(t *testing.T) {
+	var buf bytes.Buffer
+	ts := NewTableStream(&buf)
+
+	if err := ts.Offers([]domain.Offer{{Title: "Short Title", Price: 10.99, Retailer: domain.Walmart, URL: "https://example.com/1"}}); err != nil {
+		t.Fatalf("Offers() error = %v", err)
+	}
+	if err := ts.Failed(domain.Amazon, errors.New("network error")); err != nil {
+		t.Fatalf("Failed() error = %v", err)
+	}
+	if err := ts.Offers([]domain.Offer{{Title: "Another", Price: 120.5, Retailer: domain.Walmart, URL: "https://example.com/2"}}); err != nil {
+		t.Fatalf("Offers() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 rows and a failure line, got:\n%s", buf.String())
+	}
+	if strings.Count(buf.String(), "Retailer") != 1 {
+		t.Errorf("header written more than once:\n%s", buf.String())
+	}
+	if lines[2] != "Amazon: network error" {
+		t.Errorf("unexpected failure line %q", lines[2])
+	}
+	// URLs of rows from different batches start in the same column.
+	if strings.Index(lines[1], "https://") != strings.Index(lines[3], "https://") {
+		t.Errorf("rows not aligned:\n%s", buf.String())
+	}
+}
+
+func TestTableStream_Terminal(t *testing.T) {
+	var buf bytes.Buffer
+	ts := NewTableStream(&buf, TableOptions{Terminal: Terminal{Color: true, Width: 100}})
+	offers := []domain.Offer{
+		{Title: "ソニー WH-1000XM5 ワイヤレスノイズキャンセリングヘッドホン ブラック 大型", Price: 39800, Retailer: domain.AmazonJP, URL: "https://www.amazon.co.jp/Sony-WH-1000XM5-Wireless-Cancelling-Headphones/dp/B09Y2MYL5C"},
+		{Title: "Sony WH-1000XM5", Price: 41000, Retailer: domain.AmazonJP, URL: "https://www.amazon.co.jp/dp/B09Y2MYL5D"},
+	}
+	if err := ts.Offers(offers); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Failed(domain.Walmart, errors.New("network error")); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, line := range lines[1:3] {
+		if w := cellWidth(line); w > 100 {
+			t.Errorf("line is %d columns, want at most 100: %q", w, line)
+		}
+	}
+	if !strings.Contains(lines[1], "\033[32m¥39,800\033[0m") || strings.Contains(lines[2], "\033[32m") {
+		t.Errorf("cheapest not highlighted alone:\n%s", buf.String())
+	}
+	if i, j := strings.Index(lines[1], "Amazon JP"), strings.Index(lines[2], "Amazon JP"); cellWidth(lines[1][:i]) != cellWidth(lines[2][:j]) {
+		t.Errorf("rows not aligned:\n%s", buf.String())
+	}
+	if lines[3] != "\033[31mWalmart: network error\033[0m" {
+		t.Errorf("failure line = %q", lines[3])
+	}
+}
@@ -3,21 +3,213 @@ package render
 import (
 	"fmt"
 	"io"
-	"text/tabwriter"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"savvyshopper/domain"
 )
 
-// Table writes the offers to w in a tabular format.
-func Table(w io.Writer, offers []domain.Offer) error {
-	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "Title\tPrice\tRetailer\tURL")
+// TableOptions controls optional table columns and how the table fits the
+// terminal.
+type TableOptions struct {
+	// Verbose adds a relevance column.
+	Verbose bool
+	// Landed adds shipping, estimated tax and landed cost columns.
+	Landed bool
+	// Discounts adds list price and discount columns. Table adds them
+	// anyway when an offer is discounted.
+	Discounts bool
+	// Deals adds deal score and buy-or-wait advice columns.
+	Deals bool
+	// URLs is how the URL column shows links; the zero value is URLFull.
+	URLs URLMode
+	// Terminal sets the width to fit the table into and whether to
+	// highlight the cheapest offer from each retailer in color.
+	Terminal Terminal
+}
+
+// URLMode is how tables show offer URLs.
+type URLMode string
+
+const (
+	// URLFull shows URLs as they are.
+	URLFull URLMode = "full"
+	// URLShort drops the scheme, "www.", query and fragment.
+	URLShort URLMode = "short"
+	// URLNone leaves out the URL column.
+	URLNone URLMode = "none"
+)
+
+// ParseURLMode validates a --urls flag value. Empty means URLFull.
+func ParseURLMode(s string) (URLMode, error) {
+	switch m := URLMode(s); m {
+	case "", URLFull:
+		return URLFull, nil
+	case URLShort, URLNone:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid --urls %q: want full, short or none", s)
+}
+
+// Column widths, in terminal columns.
+const (
+	// maxTitleWidth caps titles even when there is room.
+	maxTitleWidth = 60
+	// minTitleWidth and minURLWidth are as far as titles and URLs are cut to
+	// fit the terminal.
+	minTitleWidth = 24
+	minURLWidth   = 20
+)
+
+// header returns the column names for opts.
+func (opts TableOptions) header() []string {
+	cols := []string{"Title", "Price", "Retailer"}
+	if opts.Discounts {
+		cols = append(cols, "List", "Off")
+	}
+	if opts.Landed {
+		cols = append(cols, "Shipping", "Tax", "Landed")
+	}
+	if opts.Deals {
+		cols = append(cols, "Deal", "Advice")
+	}
+	if opts.Verbose {
+		cols = append(cols, "Relevance")
+	}
+	if opts.URLs == URLNone {
+		return cols
+	}
+	return append(cols, "URL")
+}
+
+// row returns offer's cells after the title, price and retailer, for opts.
+func (opts TableOptions) row(offer domain.Offer) []string {
+	var cols []string
+	if opts.Discounts {
+		off := ""
+		if d := offer.Discount(); d >= 0.005 {
+			off = fmt.Sprintf("-%.0f%%", d*100)
+		}
+		cols = append(cols, formatPrice(max(offer.ListPrice, offer.Price), offer.Retailer), off)
+	}
+	if opts.Landed {
+		cols = append(cols, formatPrice(offer.Shipping, offer.Retailer), formatPrice(offer.Tax, offer.Retailer), formatPrice(offer.Landed(), offer.Retailer))
+	}
+	if opts.Deals {
+		cols = append(cols, strconv.Itoa(offer.Deal.Score), advice(offer.Deal))
+	}
+	if opts.Verbose {
+		cols = append(cols, fmt.Sprintf("%.2f", offer.Relevance))
+	}
+	switch opts.URLs {
+	case URLNone:
+		return cols
+	case URLShort:
+		return append(cols, shortURL(offer.URL))
+	}
+	return append(cols, offer.URL)
+}
+
+// cells returns all of offer's cells for opts, with the title cut to
+// maxTitleWidth and the price in green if best says it is its retailer's
+// cheapest.
+func (opts TableOptions) cells(offer domain.Offer, best map[domain.Retailer]float64) []string {
+	price := formatPrice(offer.EffectivePrice(), offer.Retailer)
+	if p, ok := best[offer.Retailer]; ok && offer.EffectivePrice() == p {
+		price = opts.Terminal.Paint(Green, price)
+	}
+	return append([]string{truncateCells(offer.Title, maxTitleWidth), price, string(offer.Retailer)}, opts.row(offer)...)
+}
+
+// Table writes the offers to w in a tabular format. The price shown is the
+// effective price after the shopper's savings. Prices are formatted for each
+// offer's marketplace, in its local currency. Given a terminal width, titles
+// and then URLs are cut short to fit it.
+func Table(w io.Writer, offers []domain.Offer, optsOpt ...TableOptions) error {
+	var opts TableOptions
+	if len(optsOpt) > 0 {
+		opts = optsOpt[0]
+	}
+
+	if !opts.Discounts {
+		opts.Discounts = slices.ContainsFunc(offers, discounted)
+	}
+
+	var g grid
+	g.add(opts.header()...)
+	best := cheapestByRetailer(offers)
 	for _, offer := range offers {
-		title := offer.Title
-		if len(title) > 60 {
-			title = title[:60]
+		g.add(opts.cells(offer, best)...)
+	}
+	if opts.Terminal.Width > 0 {
+		fitColumns(&g, opts.Terminal.Width, opts.URLs != URLNone)
+	}
+	return g.write(w)
+}
+
+// fitColumns cuts the title column, then the URL column if the table has
+// one, so the rows fit in width terminal columns, as far as minTitleWidth and
+// minURLWidth. The header row is left alone.
+func fitColumns(g *grid, width int, hasURL bool) {
+	widths := g.widths()
+	total := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	shrink := func(col, floor int) {
+		if total <= width || widths[col] <= floor {
+			return
+		}
+		target := max(widths[col]-(total-width), floor)
+		for _, row := range g.rows[1:] {
+			row[col] = truncateCells(row[col], target)
 		}
-		fmt.Fprintf(tw, "%s\t$%.2f\t%s\t%s\n", title, offer.Price, offer.Retailer, offer.URL)
+		total -= widths[col] - target
+		widths[col] = target
+	}
+	shrink(0, minTitleWidth)
+	if hasURL {
+		shrink(len(widths)-1, minURLWidth)
 	}
-	return tw.Flush()
+}
+
+// cheapestByRetailer returns the lowest effective price each retailer
+// offers.
+func cheapestByRetailer(offers []domain.Offer) map[domain.Retailer]float64 {
+	best := make(map[domain.Retailer]float64)
+	for _, o := range offers {
+		if p, ok := best[o.Retailer]; !ok || o.EffectivePrice() < p {
+			best[o.Retailer] = o.EffectivePrice()
+		}
+	}
+	return best
+}
+
+// shortURL drops raw's scheme, "www.", query and fragment, such as
+// amazon.com/dp/B0D1XD1ZV3. Unparseable URLs are returned as is.
+func shortURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return strings.TrimPrefix(u.Host, "www.") + strings.TrimSuffix(u.Path, "/")
+}
+
+// advice describes d's advice and its reason, such as "buy now: at its
+// 90-day low".
+func advice(d domain.Deal) string {
+	switch d.Advice {
+	case domain.AdviceBuy:
+		return "buy now: " + d.Reason
+	case domain.AdviceWait:
+		return "wait: " + d.Reason
+	}
+	return ""
+}
+
+// discounted reports whether o sells below its list price or has savings.
+func discounted(o domain.Offer) bool {
+	return o.ListPrice > o.Price || o.Savings > 0
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -33,3 +34,139 @@ func TestTable_Golden(t *testing.T) {
 		t.Errorf("Table() output mismatch:\nGot:\n%s\nExpected:\n%s", got, expected)
 	}
 }
+
+func TestTable_Verbose(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro", Price: 199.99, Retailer: domain.Amazon, URL: "https://example.com/1", Relevance: 1},
+		{Title: "Case for AirPods Pro", Price: 9.99, Retailer: domain.Walmart, URL: "https://example.com/2", Relevance: 0.25},
+	}
+
+	var buf bytes.Buffer
+	if err := Table(&buf, offers, TableOptions{Verbose: true}); err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], "Relevance") {
+		t.Errorf("verbose header missing Relevance column: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "1.00") || !strings.Contains(lines[2], "0.25") {
+		t.Errorf("verbose rows missing relevance scores:\n%s", buf.String())
+	}
+}
+
+func TestTable_Landed(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "AirPods Pro 2", Price: 189.99, Shipping: 5, Tax: 16.39, Retailer: domain.Amazon, URL: "https://example.com/1"},
+	}
+	var buf bytes.Buffer
+	if err := Table(&buf, offers, TableOptions{Landed: true}); err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got := strings.Fields(lines[0]); !slices.Equal(got, []string{"Title", "Price", "Retailer", "Shipping", "Tax", "Landed", "URL"}) {
+		t.Errorf("header = %q", got)
+	}
+	if got := strings.Fields(lines[1]); !slices.Equal(got[5:8], []string{"$5.00", "$16.39", "$211.38"}) {
+		t.Errorf("row = %q, want shipping, tax and landed cost", lines[1])
+	}
+}
+
+func TestTable_FitsWidth(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "Apple AirPods Pro 2 Wireless Earbuds, Active Noise Cancellation, Hearing Aid Feature", Price: 189.99, Retailer: domain.Amazon, URL: "https://www.amazon.com/Apple-Cancellation-Transparency-Personalized-High-Fidelity/dp/B0D1XD1ZV3"},
+		{Title: "AirPods Pro", Price: 199, Retailer: domain.Walmart, URL: "https://www.walmart.com/ip/5689919121"},
+	}
+	for _, width := range []int{120, 90} {
+		var buf bytes.Buffer
+		if err := Table(&buf, offers, TableOptions{Terminal: Terminal{Width: width}}); err != nil {
+			t.Fatalf("Table() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		for _, line := range lines[:2] {
+			if w := cellWidth(line); w > width {
+				t.Errorf("width %d: line is %d columns: %q", width, w, line)
+			}
+		}
+		if !strings.Contains(lines[2], "AirPods Pro") || !strings.Contains(lines[2], "walmart.com") {
+			t.Errorf("width %d: short row cut: %q", width, lines[2])
+		}
+	}
+
+	// Titles and URLs are never cut below their minimums.
+	var narrow bytes.Buffer
+	if err := Table(&narrow, offers, TableOptions{Terminal: Terminal{Width: 40}}); err != nil {
+		t.Fatal(err)
+	}
+	if row := strings.Split(narrow.String(), "\n")[1]; !strings.HasPrefix(row, "Apple AirPods Pro 2 Wir…  ") || !strings.HasSuffix(row, "https://www.amazon.…") {
+		t.Errorf("narrow row = %q", row)
+	}
+
+	// With no width, only the title cap applies.
+	var buf bytes.Buffer
+	if err := Table(&buf, offers); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), offers[0].URL) || !strings.Contains(buf.String(), "Active Noise Cancella…") {
+		t.Errorf("Table() without width = %q", buf.String())
+	}
+}
+
+func TestTable_URLs(t *testing.T) {
+	offers := []domain.Offer{{Title: "AirPods", Price: 189.99, Retailer: domain.Amazon, URL: "https://www.amazon.com/dp/B0D1XD1ZV3/?tag=x&th=1#reviews"}}
+	tests := []struct {
+		mode URLMode
+		want string
+	}{
+		{URLFull, "Amazon    https://www.amazon.com/dp/B0D1XD1ZV3/?tag=x&th=1#reviews"},
+		{URLShort, "Amazon    amazon.com/dp/B0D1XD1ZV3"},
+		{URLNone, "Amazon"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Table(&buf, offers, TableOptions{URLs: tt.mode}); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if !strings.HasSuffix(lines[1], tt.want) {
+			t.Errorf("Table(%s) row = %q, want suffix %q", tt.mode, lines[1], tt.want)
+		}
+		if hasURL := strings.HasSuffix(lines[0], "URL"); hasURL != (tt.mode != URLNone) {
+			t.Errorf("Table(%s) header = %q", tt.mode, lines[0])
+		}
+	}
+	if _, err := ParseURLMode("tiny"); err == nil {
+		t.Error("ParseURLMode(tiny) error = nil")
+	}
+}
+
+func TestTable_Color(t *testing.T) {
+	offers := []domain.Offer{
+		{Title: "A", Price: 19.99, Retailer: domain.Amazon},
+		{Title: "B", Price: 29.99, Retailer: domain.Amazon},
+		{Title: "C", Price: 24.99, Retailer: domain.Walmart},
+	}
+	var buf bytes.Buffer
+	if err := Table(&buf, offers, TableOptions{Terminal: Terminal{Color: true}}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"\033[32m$19.99\033[0m", "\033[32m$24.99\033[0m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Table() missing highlighted %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\033[32m$29.99") {
+		t.Errorf("Table() highlighted a price that isn't the retailer's cheapest:\n%s", out)
+	}
+	// Colored cells still line up.
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if cellWidth(lines[1]) != cellWidth(lines[2]) {
+		t.Errorf("rows misaligned:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := Table(&buf, offers); err != nil || strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Table() without color = %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/price"
+)
+
+// View is what user-defined output templates are executed with.
+type View struct {
+	// Query is what was searched for.
+	Query string
+	// Offers are the results, after filtering and sorting, as in the table.
+	Offers []domain.Offer
+	// Retailers reports how each retailer searched answered, by name.
+	Retailers []RetailerView
+	// Started is when the search began; Elapsed is how long it took.
+	Started time.Time
+	Elapsed time.Duration
+}
+
+// RetailerView is how one retailer answered a search.
+type RetailerView struct {
+	Retailer domain.Retailer
+	// Status is "ok", "no results" or "failed".
+	Status string
+	// Offers is the number of offers the retailer returned, before
+	// filtering.
+	Offers int
+	// Error describes the failure, if Status is "failed".
+	Error string
+	// Elapsed is the time from the start of the search to the answer.
+	Elapsed time.Duration
+}
+
+// Retailer statuses in a RetailerView.
+const (
+	StatusOK        = "ok"
+	StatusNoResults = "no results"
+	StatusFailed    = "failed"
+)
+
+// NewView builds the view of res, a search for query.
+func NewView(query string, res price.SearchResult) View {
+	v := View{Query: query, Offers: res.Offers, Started: res.Started, Elapsed: res.Elapsed}
+	for _, r := range res.Retailers {
+		rv := RetailerView{Retailer: r.Retailer, Status: StatusOK, Offers: r.Offers, Elapsed: r.Elapsed}
+		switch {
+		case r.Err != nil:
+			rv.Status, rv.Error = StatusFailed, r.Err.Error()
+		case r.Offers == 0:
+			rv.Status = StatusNoResults
+		}
+		v.Retailers = append(v.Retailers, rv)
+	}
+	return v
+}
+
+// templateFuncs are the helpers available to output templates.
+var templateFuncs = template.FuncMap{
+	// money formats an amount in the currency of a retailer's marketplace:
+	// {{money .Price .Retailer}}.
+	"money": formatPrice,
+	// price is an offer's effective price, formatted: {{price .}}.
+	"price": func(o domain.Offer) string { return formatPrice(o.EffectivePrice(), o.Retailer) },
+	// truncate shortens a value to n terminal columns, ending in "…" if cut:
+	// {{.Title | truncate 40}}.
+	"truncate": func(n int, v any) string { return truncateCells(fmt.Sprint(v), n) },
+	// pad and padLeft pad a value with spaces to n terminal columns, on the
+	// right or left: {{pad 10 .Retailer}}.
+	"pad":     func(n int, v any) string { return padCells(fmt.Sprint(v), n) },
+	"padLeft": func(n int, v any) string { return padCellsLeft(fmt.Sprint(v), n) },
+	// duration rounds d to the millisecond: {{duration .Elapsed}}.
+	"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}
+
+// ParseTemplate parses text as an output template, with the helper functions
+// money, price, truncate, pad, padLeft and duration.
+func ParseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// ParseTemplateFile parses the output template in the file at path, as
+// ParseTemplate does.
+func ParseTemplateFile(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return ParseTemplate(string(text))
+}
+
+// Template executes t with view and writes the output to w.
+func Template(w io.Writer, t *template.Template, view View) error {
+	if err := t.Execute(w, view); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
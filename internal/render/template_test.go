@@ -0,0 +1,89 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/price"
+)
+
+func TestTemplate(t *testing.T) {
+	res := price.SearchResult{
+		Offers: []domain.Offer{
+			{Title: "Apple AirPods Pro 2 Wireless Earbuds", Price: 189.99, Savings: 10, Retailer: domain.Amazon},
+			{Title: "AirPods Pro", Price: 1299, Retailer: domain.AmazonDE},
+		},
+		Retailers: []price.RetailerResult{
+			{Retailer: domain.Amazon, Offers: 3, Elapsed: 120400 * time.Microsecond},
+			{Retailer: domain.AmazonDE, Offers: 1},
+			{Retailer: domain.Walmart, Err: errors.New("network error: timeout")},
+			{Retailer: domain.WalmartCA},
+		},
+		Elapsed: 1234567 * time.Microsecond,
+	}
+	tests := []struct {
+		name, text, want string
+	}{
+		{"fields", `{{range .Offers}}{{.Retailer}} {{.Price}};{{end}}`, "Amazon 189.99;Amazon DE 1299;"},
+		{"money", `{{range .Offers}}{{money .Price .Retailer}}|{{price .}};{{end}}`, "$189.99|$179.99;1.299,00 €|1.299,00 €;"},
+		{"truncate", `{{(index .Offers 0).Title | truncate 12}}|{{(index .Offers 1).Title | truncate 12}}`, "Apple AirPo…|AirPods Pro"},
+		{"pad", `[{{pad 10 (index .Offers 1).Retailer}}][{{padLeft 6 "€"}}][{{pad 2 "toolong"}}]`, "[Amazon DE ][     €][toolong]"},
+		{"status", `{{.Query}} {{duration .Elapsed}}{{range .Retailers}}; {{.Retailer}} {{.Status}} {{.Offers}}{{with .Error}} ({{.}}){{end}}{{end}}`,
+			"airpods 1.235s; Amazon ok 3; Amazon DE ok 1; Walmart failed 0 (network error: timeout); Walmart CA no results 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.text)
+			if err != nil {
+				t.Fatalf("ParseTemplate() error = %v", err)
+			}
+			var buf bytes.Buffer
+			if err := Template(&buf, tmpl, NewView("airpods", res)); err != nil {
+				t.Fatalf("Template() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Template() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	if _, err := ParseTemplate("{{range .Offers}}"); err == nil {
+		t.Error("ParseTemplate(unclosed range) error = nil")
+	}
+	if _, err := ParseTemplate("{{upper .Query}}"); err == nil {
+		t.Error("ParseTemplate(unknown function) error = nil")
+	}
+	tmpl, err := ParseTemplate("{{.Nope}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Template(&bytes.Buffer{}, tmpl, View{}); err == nil || !strings.Contains(err.Error(), "failed to execute template") {
+		t.Errorf("Template(unknown field) error = %v", err)
+	}
+}
+
+func TestParseTemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offers.tmpl")
+	if err := os.WriteFile(path, []byte("{{len .Offers}} offers for {{.Query}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplateFile(path)
+	if err != nil {
+		t.Fatalf("ParseTemplateFile() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Template(&buf, tmpl, View{Query: "airpods"}); err != nil || buf.String() != "0 offers for airpods\n" {
+		t.Errorf("Template() = %q, %v", buf.String(), err)
+	}
+	if _, err := ParseTemplateFile(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("ParseTemplateFile(missing) error = nil")
+	}
+}
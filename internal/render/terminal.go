@@ -0,0 +1,58 @@
+package render
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// Terminal describes where output is going.
+type Terminal struct {
+	// Color enables ANSI colors.
+	Color bool
+	// Width is the number of columns to fit tables into, or 0 for no limit.
+	Width int
+}
+
+// DetectTerminal works out how to write to w. Colors are used only when w is
+// a terminal and NO_COLOR is unset or empty (see https://no-color.org) and
+// TERM is not "dumb". The width is $COLUMNS if set, and otherwise the
+// terminal's width; output that is not a terminal has no width limit unless
+// COLUMNS is set, so piped output is not cut short.
+func DetectTerminal(w io.Writer) Terminal {
+	f, ok := w.(*os.File)
+	if !ok {
+		return Terminal{}
+	}
+	var t Terminal
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		t.Width = n
+	}
+	if fi, err := f.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return t
+	}
+	t.Color = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	if t.Width == 0 {
+		t.Width = terminalWidth(f)
+	}
+	return t
+}
+
+// Color is an ANSI SGR color code.
+type Color string
+
+// Colors used in output.
+const (
+	Red    Color = "31"
+	Green  Color = "32"
+	Yellow Color = "33"
+)
+
+// Paint wraps s in c if the terminal takes colors, and returns it as is
+// otherwise.
+func (t Terminal) Paint(c Color, s string) string {
+	if !t.Color || s == "" {
+		return s
+	}
+	return "\033[" + string(c) + "m" + s + "\033[0m"
+}
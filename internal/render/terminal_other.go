@@ -0,0 +1,11 @@
+//go:build !unix
+
+package render
+
+import "os"
+
+// terminalWidth returns 0: the width of terminals is only looked up on Unix.
+// Set COLUMNS to fit tables to the window.
+func terminalWidth(*os.File) int {
+	return 0
+}
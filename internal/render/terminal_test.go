@@ -0,0 +1,38 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectTerminal(t *testing.T) {
+	if got := DetectTerminal(&bytes.Buffer{}); got != (Terminal{}) {
+		t.Errorf("DetectTerminal(buffer) = %+v, want no color or width", got)
+	}
+
+	// A file is not a terminal: no color, but COLUMNS still applies.
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	t.Setenv("COLUMNS", "100")
+	if got := DetectTerminal(f); got != (Terminal{Width: 100}) {
+		t.Errorf("DetectTerminal(file) = %+v, want width 100 only", got)
+	}
+	t.Setenv("COLUMNS", "wide")
+	if got := DetectTerminal(f); got != (Terminal{}) {
+		t.Errorf("DetectTerminal(file, bad COLUMNS) = %+v", got)
+	}
+}
+
+func TestTerminal_Paint(t *testing.T) {
+	if got := (Terminal{Color: true}).Paint(Red, "Error"); got != "\033[31mError\033[0m" {
+		t.Errorf("Paint() = %q", got)
+	}
+	if got := (Terminal{}).Paint(Red, "Error"); got != "Error" {
+		t.Errorf("Paint() without color = %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package render
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ellipsis ends text cut short to fit a column.
+const ellipsis = "…"
+
+// runeWidth is the number of terminal columns r takes: 2 for East Asian wide
+// characters and most emoji, 0 for combining marks and other invisible
+// characters, and 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r == utf8.RuneError:
+		return 1
+	case !unicode.IsPrint(r) && !unicode.IsSpace(r),
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// cellWidth is the number of terminal columns s takes, not counting ANSI
+// escape sequences.
+func cellWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += runeWidth(r)
+		i += size
+	}
+	return n
+}
+
+// escapeLen returns the length of the ANSI escape sequence s starts with, if
+// any.
+func escapeLen(s string) int {
+	if !strings.HasPrefix(s, "\033[") {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if c := s[i]; c >= 0x40 && c <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// truncateCells shortens s to at most n terminal columns, ending it in an
+// ellipsis if anything was cut. Characters are never split.
+func truncateCells(s string, n int) string {
+	if cellWidth(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > n-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + ellipsis
+}
+
+// padCells pads s with spaces on the right to n terminal columns.
+func padCells(s string, n int) string {
+	return s + strings.Repeat(" ", max(n-cellWidth(s), 0))
+}
+
+// padCellsLeft pads s with spaces on the left to n terminal columns.
+func padCellsLeft(s string, n int) string {
+	return strings.Repeat(" ", max(n-cellWidth(s), 0)) + s
+}
+
+// columnGap separates grid columns.
+const columnGap = "  "
+
+// grid lays out rows of cells in aligned columns, measured in terminal
+// columns so wide characters and colors line up. The last cell of each row is
+// not padded.
+type grid struct {
+	rows [][]string
+}
+
+func (g *grid) add(cells ...string) {
+	g.rows = append(g.rows, cells)
+}
+
+// widths returns the widest cell in each column, the last included.
+func (g *grid) widths() []int {
+	var widths []int
+	for _, row := range g.rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], cellWidth(cell))
+		}
+	}
+	return widths
+}
+
+func (g *grid) write(w io.Writer) error {
+	widths := g.widths()
+	var b strings.Builder
+	for _, row := range g.rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(padCells(cell, widths[i]) + columnGap)
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCellWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"AirPods", 7},
+		{"Sony™ Café", 10},
+		{"Café", 4},                 // e + combining acute accent
+		{"ソニー ヘッドホン", 17},            // wide katakana
+		{"Ｗｉｄｅ", 8},                  // fullwidth Latin
+		{"🎧 Headphones", 13},         // wide emoji
+		{"\033[32m$19.99\033[0m", 6}, // colors take no room
+	}
+	for _, tt := range tests {
+		if got := cellWidth(tt.s); got != tt.want {
+			t.Errorf("cellWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateCells(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"AirPods Pro", 20, "AirPods Pro"},
+		{"AirPods Pro", 11, "AirPods Pro"},
+		{"AirPods Pro", 8, "AirPods…"},
+		{"Sony™ WH-1000XM5", 6, "Sony™…"},
+		{"ソニーヘッドホン", 6, "ソニ…"}, // a wide character is never split
+		{"ソニーヘッドホン", 7, "ソニー…"},
+		{"AirPods", 0, ""},
+	}
+	for _, tt := range tests {
+		got := truncateCells(tt.s, tt.n)
+		if got != tt.want {
+			t.Errorf("truncateCells(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+		if !utf8.ValidString(got) || cellWidth(got) > tt.n {
+			t.Errorf("truncateCells(%q, %d) = %q: invalid or too wide", tt.s, tt.n, got)
+		}
+	}
+}
+
+func TestGrid_WideCharacters(t *testing.T) {
+	var g grid
+	g.add("Title", "Price", "URL")
+	g.add("ソニー ヘッドホン", "¥39,800", "https://www.amazon.co.jp/dp/B0BXYZ1234")
+	g.add("Café Grinder", "\033[32m€49,99\033[0m", "https://www.amazon.de/dp/B0ABC12345")
+	var buf bytes.Buffer
+	if err := g.write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// Every URL starts at the same terminal column.
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		i := strings.LastIndex(line, "  ")
+		if got := cellWidth(line[:i+2]); got != 28 {
+			t.Errorf("URL in %q starts at column %d, want 28", line, got)
+		}
+	}
+}
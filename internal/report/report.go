@@ -0,0 +1,126 @@
+// Package report summarises the price history per product: the first, last,
+// lowest and highest price seen for each listing, with listings of the same
+// product at different retailers grouped together.
+package report
+
+import (
+	"sort"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/match"
+)
+
+// Report covers the prices recorded from From up to To.
+type Report struct {
+	// From and To bound the report; either may be zero for an open end.
+	From, To time.Time
+	Products []Product
+}
+
+// Product is one product's price history, across every retailer it was
+// seen at.
+type Product struct {
+	Title string
+	// Currency is the ISO 4217 code shared by every line, or empty when the
+	// lines are in different currencies, in which case Stats is zero.
+	Currency string
+	Stats    Stats
+	Lines    []Line
+}
+
+// Line is the price history of one listing.
+type Line struct {
+	Key      string
+	Retailer domain.Retailer
+	Title    string
+	URL      string
+	Currency string
+	Stats    Stats
+	Points   []history.Point
+}
+
+// Stats summarises a run of prices.
+type Stats struct {
+	First, Last, Min, Max history.Point
+	// Count is the number of prices recorded.
+	Count int
+}
+
+// add folds p into s. Points must be added oldest first; ties for the lowest
+// or highest price keep the earliest.
+func (s *Stats) add(p history.Point) {
+	if s.Count == 0 {
+		s.First, s.Min, s.Max = p, p, p
+	}
+	s.Last = p
+	if p.Price < s.Min.Price {
+		s.Min = p
+	}
+	if p.Price > s.Max.Price {
+		s.Max = p
+	}
+	s.Count++
+}
+
+// Build groups points, oldest first, by listing and the listings into
+// products. Listings are matched on their latest title, as the compare view
+// matches search results.
+func Build(from, to time.Time, points []history.Point) Report {
+	lines := make(map[string]*Line)
+	var order []string
+	for _, p := range points {
+		l, ok := lines[p.Key]
+		if !ok {
+			l = &Line{Key: p.Key, Retailer: p.Retailer, Currency: p.Retailer.Marketplace().Currency}
+			lines[p.Key] = l
+			order = append(order, p.Key)
+		}
+		if p.Title != "" {
+			l.Title = p.Title
+		}
+		if p.URL != "" {
+			l.URL = p.URL
+		}
+		l.Points = append(l.Points, p)
+		l.Stats.add(p)
+	}
+
+	// Group listings by way of an offer standing for each.
+	offers := make([]domain.Offer, len(order))
+	for i, key := range order {
+		l := lines[key]
+		offers[i] = domain.Offer{Title: l.Title, Price: l.Stats.Last.Price, URL: key, Retailer: l.Retailer}
+	}
+	r := Report{From: from, To: to}
+	for _, c := range match.Group(offers) {
+		product := Product{Title: c.Title}
+		for _, o := range c.Offers {
+			product.Lines = append(product.Lines, *lines[o.URL])
+		}
+		product.summarise()
+		r.Products = append(r.Products, product)
+	}
+	return r
+}
+
+// summarise sets the product's currency and stats from its lines, when they
+// share a currency.
+func (p *Product) summarise() {
+	p.Currency = p.Lines[0].Currency
+	for _, l := range p.Lines {
+		if l.Currency != p.Currency {
+			p.Currency = ""
+			return
+		}
+	}
+	var points []history.Point
+	for _, l := range p.Lines {
+		points = append(points, l.Points...)
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	for _, pt := range points {
+		p.Stats.add(pt)
+	}
+}
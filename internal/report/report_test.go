@@ -0,0 +1,75 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+)
+
+func point(day int, retailer domain.Retailer, id, title string, price float64) history.Point {
+	return history.Point{
+		Time:     time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC),
+		Key:      history.ProductKey(retailer, id),
+		Retailer: retailer,
+		Title:    title,
+		Price:    price,
+	}
+}
+
+func TestBuild(t *testing.T) {
+	const airpods = "Apple AirPods Pro 2 Wireless Earbuds"
+	points := []history.Point{
+		point(1, domain.Amazon, "B0D1XD1ZV3", airpods, 199),
+		point(1, domain.Walmart, "5689919121", airpods, 189),
+		point(2, domain.Amazon, "B0D1XD1ZV3", airpods, 179),
+		point(2, domain.Amazon, "B0BDHWDR12", "Sony WH-1000XM5 Headphones", 329),
+		point(3, domain.Amazon, "B0D1XD1ZV3", airpods, 209),
+		point(3, domain.Walmart, "5689919121", airpods, 179),
+	}
+	r := Build(time.Time{}, time.Time{}, points)
+	if len(r.Products) != 2 {
+		t.Fatalf("Build() products = %d, want 2", len(r.Products))
+	}
+
+	p := r.Products[0]
+	if p.Title != airpods || p.Currency != "USD" || len(p.Lines) != 2 {
+		t.Fatalf("Build() product = %+v", p)
+	}
+	if s := p.Stats; s.Count != 5 || s.First.Price != 199 || s.Last.Price != 179 || s.Min.Price != 179 || s.Max.Price != 209 {
+		t.Errorf("product stats = %+v", s)
+	}
+	// Ties for the lowest price keep the earliest.
+	if got := p.Stats.Min.Time.Day(); got != 2 {
+		t.Errorf("product low on day %d, want 2", got)
+	}
+	amazon := p.Lines[0]
+	if amazon.Key != "amazon:B0D1XD1ZV3" || amazon.Stats.Count != 3 || amazon.Stats.Last.Price != 209 || amazon.Stats.Min.Price != 179 {
+		t.Errorf("amazon line = %+v", amazon)
+	}
+	if walmart := p.Lines[1]; walmart.Retailer != domain.Walmart || walmart.Stats.First.Price != 189 || walmart.Stats.Last.Price != 179 {
+		t.Errorf("walmart line = %+v", walmart)
+	}
+	if sony := r.Products[1]; sony.Stats.Count != 1 || sony.Lines[0].Stats.Max.Price != 329 {
+		t.Errorf("sony product = %+v", sony)
+	}
+}
+
+func TestBuild_MixedCurrency(t *testing.T) {
+	const airpods = "Apple AirPods Pro 2 Wireless Earbuds"
+	r := Build(time.Time{}, time.Time{}, []history.Point{
+		point(1, domain.Amazon, "B0D1XD1ZV3", airpods, 199),
+		point(1, domain.AmazonUK, "B0D1XD1ZV3", airpods, 179),
+	})
+	if len(r.Products) != 1 {
+		t.Fatalf("Build() products = %d, want 1", len(r.Products))
+	}
+	p := r.Products[0]
+	if p.Currency != "" || p.Stats.Count != 0 {
+		t.Errorf("mixed-currency product = %+v, want no currency or stats", p)
+	}
+	if p.Lines[1].Currency != "GBP" {
+		t.Errorf("uk line currency = %q", p.Lines[1].Currency)
+	}
+}
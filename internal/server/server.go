@@ -0,0 +1,247 @@
+// Package server exposes price search over HTTP, including live endpoints that
+// push each retailer's offers as they arrive.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/forecast"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/relevance"
+	"savvyshopper/internal/render"
+	"savvyshopper/internal/watch"
+)
+
+// Server routes the HTTP API.
+type Server struct {
+	searchers map[domain.Retailer]price.Searcher
+	mux       *http.ServeMux
+	// historyPath is the price history forecasts are made from; empty
+	// until EnableForecasts.
+	historyPath string
+}
+
+// New creates a Server.
+// If searchersOpt is provided, it uses those searchers instead of the default ones.
+func New(searchersOpt ...map[domain.Retailer]price.Searcher) *Server {
+	s := &Server{
+		searchers: price.DefaultSearchers(),
+		mux:       http.NewServeMux(),
+	}
+	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
+		s.searchers = searchersOpt[0]
+	}
+	s.mux.HandleFunc("GET /v1/search", s.handleSearch)
+	s.mux.HandleFunc("GET /v1/search/stream", s.handleStream)
+	s.mux.HandleFunc("GET /v1/ws", s.handleWebSocket)
+	s.mux.HandleFunc("GET /v1/forecast/{id}", s.handleForecast)
+	s.mux.Handle("GET /metrics", promhttp.HandlerFor(price.Registry, promhttp.HandlerOpts{}))
+	return s
+}
+
+// EnableForecasts serves forecasts for the watches kept beside the price
+// history at historyPath. Both files are reread on every request, so watches
+// added and prices recorded by the CLI show up without a restart.
+func (s *Server) EnableForecasts(historyPath string) {
+	s.historyPath = historyPath
+}
+
+// EnableCache reuses each retailer's results for the same query for ttl.
+func (s *Server) EnableCache(ttl time.Duration) {
+	s.searchers = price.NewCachedSearchers(s.searchers, ttl)
+}
+
+// retailers returns the retailers the server searches.
+func (s *Server) retailers() []domain.Retailer {
+	return slices.Collect(maps.Keys(s.searchers))
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleSearch runs a search and returns every offer at once.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	params, err := paramsFromQuery(r.URL.Query(), s.retailers())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	offers, err := price.SearchPrices(r.Context(), params.Q, s.searchers)
+	if err == nil {
+		if offers = params.apply(offers); len(offers) == 0 {
+			err = domain.ErrNoResults
+		}
+	}
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"offers": render.OffersJSON(offers)})
+}
+
+// defaultForecastDays is how far ahead a forecast looks without a days
+// parameter.
+const defaultForecastDays = 14
+
+// handleForecast forecasts a watched listing's price over the next days days.
+func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
+	if s.historyPath == "" {
+		writeError(w, http.StatusNotFound, errors.New("forecasts need the price history"))
+		return
+	}
+	days := defaultForecastDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid days: %q", v))
+			return
+		}
+		days = n
+	}
+
+	list, err := watch.Open(watch.PathFor(s.historyPath))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	wt, err := list.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	store, err := history.Open(s.historyPath)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	f, err := forecast.FromHistory(store.Points(wt.Key()), days)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"watch": wt, "forecast": render.NewForecastJSON(f)})
+}
+
+// searchParams are the options a client may give a search, as URL query
+// parameters or as a WebSocket message. They mirror the CLI flags.
+type searchParams struct {
+	// ID tags the events of a WebSocket query so a client can tell
+	// concurrent queries apart.
+	ID           string   `json:"id,omitempty"`
+	Q            string   `json:"q"`
+	Sort         string   `json:"sort,omitempty"`
+	Desc         bool     `json:"desc,omitempty"`
+	MinPrice     float64  `json:"min_price,omitempty"`
+	MaxPrice     float64  `json:"max_price,omitempty"`
+	Condition    string   `json:"condition,omitempty"`
+	InStock      bool     `json:"in_stock,omitempty"`
+	PrimeOnly    bool     `json:"prime_only,omitempty"`
+	MinRating    float64  `json:"min_rating,omitempty"`
+	Fulfillment  string   `json:"fulfillment,omitempty"`
+	MinRelevance float64  `json:"min_relevance,omitempty"`
+	Exclude      []string `json:"exclude,omitempty"`
+
+	refine price.Refinement
+}
+
+// paramsFromQuery reads and validates searchParams from URL query
+// parameters, for a search of retailers.
+func paramsFromQuery(v url.Values, retailers []domain.Retailer) (searchParams, error) {
+	p := searchParams{
+		Q:           v.Get("q"),
+		Sort:        v.Get("sort"),
+		Condition:   v.Get("condition"),
+		Fulfillment: v.Get("fulfillment"),
+		Exclude:     v["exclude"],
+	}
+	var err error
+	for name, dst := range map[string]*bool{"desc": &p.Desc, "in_stock": &p.InStock, "prime_only": &p.PrimeOnly} {
+		if s := v.Get(name); s != "" {
+			if *dst, err = strconv.ParseBool(s); err != nil {
+				return p, fmt.Errorf("invalid %s: %q", name, s)
+			}
+		}
+	}
+	for name, dst := range map[string]*float64{"min_price": &p.MinPrice, "max_price": &p.MaxPrice, "min_rating": &p.MinRating, "min_relevance": &p.MinRelevance} {
+		if s := v.Get(name); s != "" {
+			if *dst, err = strconv.ParseFloat(s, 64); err != nil {
+				return p, fmt.Errorf("invalid %s: %q", name, s)
+			}
+		}
+	}
+	return p, p.validate(retailers)
+}
+
+// validate checks the parameters for a search of retailers and prepares the
+// refinement they describe.
+func (p *searchParams) validate(retailers []domain.Retailer) error {
+	if p.Q == "" {
+		return errors.New("missing query parameter q")
+	}
+	var err error
+	if p.refine.Sort, err = price.ParseSortKey(p.Sort); err != nil {
+		return err
+	}
+	if p.refine.Sort == price.SortLanded || p.refine.Sort == price.SortDeal {
+		// The API neither estimates tax nor rates deals.
+		return fmt.Errorf("sort %q is only available in the CLI", p.refine.Sort)
+	}
+	if p.refine.Condition, err = price.ParseCondition(p.Condition); err != nil {
+		return err
+	}
+	if p.refine.Fulfillment, err = price.ParseFulfillment(p.Fulfillment); err != nil {
+		return err
+	}
+	p.refine.Desc = p.Desc
+	p.refine.MinPrice = p.MinPrice
+	p.refine.MaxPrice = p.MaxPrice
+	p.refine.InStock = p.InStock
+	p.refine.PrimeOnly = p.PrimeOnly
+	p.refine.MinRating = p.MinRating
+	return p.refine.Check(retailers)
+}
+
+// apply scores offers for relevance and applies the filters and sort order.
+func (p searchParams) apply(offers []domain.Offer) []domain.Offer {
+	offers = relevance.Scorer{Exclude: p.Exclude}.Score(p.Q, offers)
+	offers = relevance.Filter(offers, p.MinRelevance)
+	return p.refine.Apply(offers)
+}
+
+// statusFor maps a search error to an HTTP status.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNoResults), errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidRef):
+		return http.StatusBadRequest
+	default:
+		// Zinc failures, including a rejected API key, are upstream problems.
+		return http.StatusBadGateway
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,377 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/watch"
+)
+
+type mockSearcher struct {
+	results []domain.Offer
+	err     error
+	latency time.Duration
+}
+
+func (m *mockSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	if m.latency > 0 {
+		time.Sleep(m.latency)
+	}
+	return m.results, m.err
+}
+
+// blockingSearcher blocks until its context is done and records that it was
+// cancelled.
+type blockingSearcher struct {
+	cancelled chan struct{}
+}
+
+func (b *blockingSearcher) Search(ctx context.Context, query string) ([]domain.Offer, error) {
+	<-ctx.Done()
+	close(b.cancelled)
+	return nil, ctx.Err()
+}
+
+func testSearchers() map[domain.Retailer]price.Searcher {
+	return map[domain.Retailer]price.Searcher{
+		domain.Amazon: &mockSearcher{results: []domain.Offer{
+			{Title: "AirPods Pro 2", Price: 199.99, URL: "https://example.com/a"},
+			{Title: "Case for AirPods Pro 2", Price: 9.99, URL: "https://example.com/case"},
+		}, latency: 30 * time.Millisecond},
+		domain.Walmart: &mockSearcher{results: []domain.Offer{
+			{Title: "AirPods Pro 2", Price: 189.00, URL: "https://example.com/w"},
+		}},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/search?q=airpods+pro+2&min_relevance=0.5&sort=price&desc=true")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var body struct {
+		Offers []struct {
+			Title    string  `json:"title"`
+			Price    float64 `json:"price"`
+			Retailer string  `json:"retailer"`
+		} `json:"offers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(body.Offers) != 2 {
+		t.Fatalf("expected accessory to be filtered, got %+v", body.Offers)
+	}
+	if body.Offers[0].Price != 199.99 || body.Offers[0].Retailer != "Amazon" {
+		t.Errorf("expected descending price order, got %+v", body.Offers)
+	}
+}
+
+func TestSearch_Exclude(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/search?q=airpods+pro+2&exclude=case")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Offers []struct {
+			Title string `json:"title"`
+		} `json:"offers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(body.Offers) != 2 {
+		t.Errorf("expected the excluded case to be dropped without min_relevance, got %+v", body.Offers)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/search?q=airpods+pro+2")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`savvyshopper_retailer_requests_total{outcome="ok",retailer="Walmart"}`,
+		"savvyshopper_search_duration_seconds_bucket",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics missing %q", want)
+		}
+	}
+}
+
+func TestEnableCache(t *testing.T) {
+	handler := New(testSearchers())
+	handler.EnableCache(time.Minute)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for range 2 {
+		resp, err := http.Get(srv.URL + "/v1/search?q=airpods+pro+2")
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if want := `savvyshopper_cache_requests_total{result="hit",retailer="Walmart"}`; !strings.Contains(string(body), want) {
+		t.Errorf("/metrics missing %q after a repeated search", want)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	dir := t.TempDir()
+	historyPath := filepath.Join(dir, "history.jsonl")
+	store, err := history.Open(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offer := domain.Offer{Title: "AirPods Pro 2", ProductID: "B0D1XD1ZV3", Retailer: domain.Amazon}
+	start := time.Now().AddDate(0, 0, -20)
+	for i := range 20 {
+		offer.Price = 200 - float64(i)
+		if err := store.Record([]domain.Offer{offer}, start.AddDate(0, 0, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := watch.Open(watch.PathFor(historyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Add(watch.Watch{Retailer: domain.Amazon, ProductID: "B0D1XD1ZV3"})
+	list.Add(watch.Watch{Retailer: domain.Walmart, ProductID: "5689919121"})
+	if err := list.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(testSearchers())
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		return resp
+	}
+	if resp := get("/v1/forecast/1"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status without history = %d, want 404", resp.StatusCode)
+	}
+
+	s.EnableForecasts(historyPath)
+	resp := get("/v1/forecast/1?days=7")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Watch    struct{ ID string } `json:"watch"`
+		Forecast struct {
+			History int `json:"history_days"`
+			Low     struct {
+				Price float64 `json:"price"`
+			} `json:"low"`
+			Days []struct{} `json:"days"`
+		} `json:"forecast"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if body.Watch.ID != "1" || body.Forecast.History != 20 || len(body.Forecast.Days) != 7 || body.Forecast.Low.Price >= 181 {
+		t.Errorf("body = %+v, want 7 days falling below the last price of 181", body)
+	}
+
+	for path, want := range map[string]int{
+		"/v1/forecast/9":        http.StatusNotFound,
+		"/v1/forecast/1?days=0": http.StatusBadRequest,
+		"/v1/forecast/2":        http.StatusUnprocessableEntity,
+	} {
+		resp := get(path)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s status = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestSearch_BadRequest(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	for _, path := range []string{"/v1/search", "/v1/search?q=x&sort=cheapest", "/v1/search?q=x&sort=deal", "/v1/search?q=x&sort=landed", "/v1/search/stream?q=x&min_price=abc"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestSearch_PriceLimitAcrossCurrencies(t *testing.T) {
+	searchers := testSearchers()
+	searchers[domain.AmazonJP] = &mockSearcher{}
+	srv := httptest.NewServer(New(searchers))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/search?q=airpods&max_price=200")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 for a price limit across USD and JPY", resp.StatusCode)
+	}
+}
+
+func TestStream_SSE(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/search/stream?q=airpods+pro+2")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	var names []string
+	var offerRetailers []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			names = append(names, name)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var ev eventJSON
+			if err := json.Unmarshal([]byte(data), &ev); err != nil {
+				t.Fatalf("bad event data %q: %v", data, err)
+			}
+			if ev.Type == price.EventOffers {
+				offerRetailers = append(offerRetailers, string(ev.Retailer))
+			}
+		}
+	}
+
+	want := "started,started,offers,offers,done"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+	if strings.Join(offerRetailers, ",") != "Walmart,Amazon" {
+		t.Errorf("expected faster Walmart first, got %v", offerRetailers)
+	}
+}
+
+func TestStream_DisconnectCancels(t *testing.T) {
+	blocker := &blockingSearcher{cancelled: make(chan struct{})}
+	srv := httptest.NewServer(New(map[domain.Retailer]price.Searcher{domain.Amazon: blocker}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/search/stream?q=x", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	// Wait for the first event so the search is running, then hang up.
+	bufio.NewReader(resp.Body).ReadString('\n')
+	cancel()
+	resp.Body.Close()
+
+	select {
+	case <-blocker.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("search was not cancelled after client disconnect")
+	}
+}
+
+func TestWebSocket_MultipleQueries(t *testing.T) {
+	srv := httptest.NewServer(New(testSearchers()))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/v1/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial error = %v", err)
+	}
+	defer conn.CloseNow()
+
+	for _, q := range []searchParams{{ID: "1", Q: "airpods pro 2"}, {ID: "2", Q: "airpods", Sort: "bogus"}, {ID: "3", Q: "airpods pro 2"}} {
+		if err := wsjson.Write(ctx, conn, q); err != nil {
+			t.Fatalf("Write error = %v", err)
+		}
+	}
+
+	done := map[string]bool{}
+	failed := map[string]bool{}
+	for len(done) < 2 {
+		var ev eventJSON
+		if err := wsjson.Read(ctx, conn, &ev); err != nil {
+			t.Fatalf("Read error = %v", err)
+		}
+		switch ev.Type {
+		case price.EventDone:
+			done[ev.ID] = true
+		case price.EventFailed:
+			failed[ev.ID] = true
+		}
+	}
+	if !done["1"] || !done["3"] {
+		t.Errorf("expected both valid queries to finish, got %v", done)
+	}
+	if !failed["2"] {
+		t.Errorf("expected invalid query to be rejected, got %v", failed)
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/render"
+)
+
+// eventJSON is the wire form of a price.SearchEvent for both Server-Sent
+// Events and WebSocket clients.
+type eventJSON struct {
+	ID        string             `json:"id,omitempty"`
+	Type      price.EventKind    `json:"type"`
+	Retailer  domain.Retailer    `json:"retailer,omitempty"`
+	Offers    []render.OfferJSON `json:"offers,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	ElapsedMS int64              `json:"elapsed_ms"`
+}
+
+// newEventJSON converts ev, applying params to any offers it carries.
+func newEventJSON(params searchParams, ev price.SearchEvent) eventJSON {
+	out := eventJSON{
+		ID:        params.ID,
+		Type:      ev.Kind,
+		Retailer:  ev.Retailer,
+		ElapsedMS: ev.Elapsed.Milliseconds(),
+	}
+	if ev.Kind == price.EventOffers {
+		out.Offers = render.OffersJSON(params.apply(ev.Offers))
+	}
+	if ev.Err != nil {
+		out.Error = ev.Err.Error()
+	}
+	return out
+}
+
+// handleStream pushes each retailer's status and offers as Server-Sent Events.
+// The search is cancelled when the client disconnects.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	params, err := paramsFromQuery(r.URL.Query(), s.retailers())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for ev := range price.StreamPrices(r.Context(), params.Q, s.searchers) {
+		data, err := json.Marshal(newEventJSON(params, ev))
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Kind, data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// handleWebSocket accepts any number of queries over one connection. Each
+// message from the client is a searchParams object; the events of concurrent
+// queries are interleaved and tagged with the query's id. Closing the
+// connection cancels every query still in flight.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	var wg sync.WaitGroup
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			break
+		}
+		var params searchParams
+		if err := json.Unmarshal(msg, &params); err != nil {
+			wsjson.Write(ctx, conn, eventJSON{Type: price.EventFailed, Error: "invalid message: " + err.Error()})
+			continue
+		}
+		if err := params.validate(s.retailers()); err != nil {
+			wsjson.Write(ctx, conn, eventJSON{ID: params.ID, Type: price.EventFailed, Error: err.Error()})
+			continue
+		}
+
+		wg.Add(1)
+		go func(params searchParams) {
+			defer wg.Done()
+			for ev := range price.StreamPrices(ctx, params.Q, s.searchers) {
+				if err := wsjson.Write(ctx, conn, newEventJSON(params, ev)); err != nil {
+					return
+				}
+			}
+		}(params)
+	}
+
+	cancel()
+	wg.Wait()
+	conn.Close(websocket.StatusNormalClosure, "")
+}
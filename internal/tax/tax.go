@@ -0,0 +1,168 @@
+// Package tax estimates the sales tax charged on an offer at checkout, from
+// a table of combined state and local rates keyed by delivery ZIP code.
+//
+// The bundled table holds average rates per state plus a few ZIP prefixes
+// with notably different local rates. It is an estimate: real rates vary by
+// city, county and product category. A JSON file in the same format
+// overrides any of its entries.
+package tax
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+
+	"savvyshopper/domain"
+)
+
+//go:embed rates.json
+var bundled []byte
+
+// Rule says how a marketplace handles sales tax.
+type Rule struct {
+	// Facilitator means the retailer collects tax on behalf of its
+	// marketplace sellers, so their offers are taxed like its own.
+	Facilitator bool `json:"facilitator"`
+	// TaxIncluded means listed prices already include tax (VAT), so no
+	// tax is added.
+	TaxIncluded bool `json:"tax_included"`
+}
+
+// Range maps the three-digit ZIP prefixes From through To to a state.
+type Range struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	State string `json:"state"`
+}
+
+// Table holds tax rates in percent and the per-marketplace rules.
+type Table struct {
+	// States maps a two-letter state code to its average combined rate.
+	States map[string]float64 `json:"states"`
+	// ZIPPrefixes maps a three-digit ZIP prefix to a rate that replaces its
+	// state's.
+	ZIPPrefixes map[string]float64 `json:"zip_prefixes"`
+	// ZIPStates maps ZIP prefixes to states.
+	ZIPStates []Range `json:"zip_states"`
+	// Retailers maps a marketplace domain, such as amazon.com, to its rule.
+	Retailers map[string]Rule `json:"retailers"`
+}
+
+// Default returns the bundled table.
+func Default() *Table {
+	var t Table
+	if err := json.Unmarshal(bundled, &t); err != nil {
+		panic(fmt.Sprintf("tax: invalid bundled rates: %v", err))
+	}
+	return &t
+}
+
+// Load returns the bundled table overridden by the file at path: its rates
+// and rules replace the bundled ones with the same key, and a zip_states list
+// replaces the bundled list.
+func Load(path string) (*Table, error) {
+	t := Default()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rates: %w", err)
+	}
+	var over Table
+	if err := json.Unmarshal(data, &over); err != nil {
+		return nil, fmt.Errorf("failed to parse tax rates %s: %w", path, err)
+	}
+	for k, v := range over.States {
+		t.States[k] = v
+	}
+	for k, v := range over.ZIPPrefixes {
+		t.ZIPPrefixes[k] = v
+	}
+	for k, v := range over.Retailers {
+		t.Retailers[k] = v
+	}
+	if len(over.ZIPStates) > 0 {
+		t.ZIPStates = over.ZIPStates
+	}
+	return t, nil
+}
+
+// zipCode matches a five-digit ZIP code with an optional +4 suffix.
+var zipCode = regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`)
+
+// Rate returns the combined sales tax rate, in percent, for deliveries to
+// zip.
+func (t *Table) Rate(zip string) (float64, error) {
+	if !zipCode.MatchString(zip) {
+		return 0, fmt.Errorf("invalid ZIP code %q: want 5 digits", zip)
+	}
+	prefix := zip[:3]
+	if rate, ok := t.ZIPPrefixes[prefix]; ok {
+		return rate, nil
+	}
+	for _, r := range t.ZIPStates {
+		if prefix >= r.From && prefix <= r.To {
+			if rate, ok := t.States[r.State]; ok {
+				return rate, nil
+			}
+			return 0, fmt.Errorf("no tax rate for %s, the state of ZIP code %s", r.State, zip)
+		}
+	}
+	return 0, fmt.Errorf("no tax rate for ZIP code %s", zip)
+}
+
+// rule returns the rule for retailer's marketplace, and whether its tax can
+// be estimated at all. US marketplaces without a rule are assumed to collect
+// tax on every offer; other marketplaces without one get no estimate, since a
+// US ZIP code says nothing about their tax.
+func (t *Table) rule(retailer domain.Retailer) (Rule, bool) {
+	m := retailer.Marketplace()
+	if r, ok := t.Retailers[m.Domain]; ok {
+		return r, true
+	}
+	return Rule{Facilitator: true}, m.Currency == "USD"
+}
+
+// Estimator works out the tax on offers delivered to one ZIP code.
+type Estimator struct {
+	table *Table
+	rate  float64
+}
+
+// NewEstimator creates an Estimator for deliveries to zip, using the bundled
+// rates or table if given.
+func NewEstimator(zip string, tableOpt ...*Table) (*Estimator, error) {
+	t := Default()
+	if len(tableOpt) > 0 && tableOpt[0] != nil {
+		t = tableOpt[0]
+	}
+	rate, err := t.Rate(zip)
+	if err != nil {
+		return nil, err
+	}
+	return &Estimator{table: t, rate: rate}, nil
+}
+
+// Tax returns the tax charged on o's effective price at checkout, rounded to
+// the cent. It is zero when the marketplace's prices include tax, for a
+// marketplace seller's offer when the retailer does not collect tax for its
+// sellers, and for marketplaces outside the US without a rule, such as
+// walmart.ca, which are left without an estimate. Shipping is not taxed.
+func (e *Estimator) Tax(o domain.Offer) float64 {
+	rule, ok := e.table.rule(o.Retailer)
+	if !ok || rule.TaxIncluded || (o.Seller != "" && !rule.Facilitator) {
+		return 0
+	}
+	return math.Round(o.EffectivePrice()*e.rate) / 100
+}
+
+// Apply returns a copy of offers with Tax set.
+func (e *Estimator) Apply(offers []domain.Offer) []domain.Offer {
+	out := make([]domain.Offer, len(offers))
+	for i, o := range offers {
+		o.Tax = e.Tax(o)
+		out[i] = o
+	}
+	return out
+}
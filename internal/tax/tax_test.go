@@ -0,0 +1,101 @@
+package tax
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		zip  string
+		want float64
+	}{
+		{"94103", 8.625},      // San Francisco prefix
+		{"95814", 8.85},       // California average
+		{"97201", 0},          // Oregon has no sales tax
+		{"10001-1234", 8.875}, // ZIP+4
+		{"05501", 6.25},       // Massachusetts prefix inside Vermont's range
+		{"73301", 8.2},        // Austin's IRS prefix belongs to Texas
+	}
+	table := Default()
+	for _, tt := range tests {
+		got, err := table.Rate(tt.zip)
+		if err != nil {
+			t.Errorf("Rate(%q) error = %v", tt.zip, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Rate(%q) = %v, want %v", tt.zip, got, tt.want)
+		}
+	}
+}
+
+func TestRate_Invalid(t *testing.T) {
+	table := Default()
+	for _, zip := range []string{"", "9410", "941033", "ABCDE", "00901"} {
+		if _, err := table.Rate(zip); err == nil {
+			t.Errorf("Rate(%q) error = nil, want error", zip)
+		}
+	}
+}
+
+func TestEstimator_Tax(t *testing.T) {
+	est, err := NewEstimator("94103")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		offer domain.Offer
+		want  float64
+	}{
+		{"retailer offer", domain.Offer{Price: 100, Shipping: 5, Retailer: domain.Amazon}, 8.63},
+		{"marketplace seller on a facilitator", domain.Offer{Price: 100, Retailer: domain.Walmart, Seller: "Gadget Outlet"}, 8.63},
+		{"VAT-inclusive marketplace", domain.Offer{Price: 100, Retailer: domain.AmazonUK}, 0},
+		{"non-US marketplace without a rule", domain.Offer{Price: 100, Retailer: domain.WalmartCA}, 0},
+		{"unknown retailer", domain.Offer{Price: 10, Retailer: domain.Retailer("Target")}, 0.86},
+	}
+	for _, tt := range tests {
+		if got := est.Tax(tt.offer); got != tt.want {
+			t.Errorf("%s: Tax() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	offers := est.Apply([]domain.Offer{{Price: 100, Shipping: 5, Retailer: domain.Amazon}})
+	if got := offers[0].Landed(); got != 113.63 {
+		t.Errorf("Landed() = %v, want 113.63", got)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	data := `{"zip_prefixes": {"958": 8.75}, "retailers": {"walmart.com": {"facilitator": false}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	est, err := NewEstimator("95814", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := est.Tax(domain.Offer{Price: 100, Retailer: domain.Amazon}); got != 8.75 {
+		t.Errorf("Tax() = %v, want 8.75 from the override", got)
+	}
+	if got := est.Tax(domain.Offer{Price: 100, Retailer: domain.Walmart, Seller: "Gadget Outlet"}); got != 0 {
+		t.Errorf("Tax() = %v, want 0 for a seller on a non-facilitator", got)
+	}
+	if _, err := table.Rate("10001"); err != nil {
+		t.Errorf("bundled rates lost after override: %v", err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "failed to read tax rates") {
+		t.Errorf("Load(missing) error = %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package telemetry configures OpenTelemetry tracing. The rest of the code
+// creates spans through the global tracer provider, which does nothing until
+// Setup or Install replaces it.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ServiceName identifies savvyshopper's spans in a tracing backend.
+const ServiceName = "savvyshopper"
+
+// Setup exports spans over OTLP/HTTP to endpoint, a URL such as
+// http://localhost:4318; without a path, spans go to the standard /v1/traces.
+// The standard OTEL_EXPORTER_OTLP_* environment
+// variables configure headers, TLS and the like. If endpoint is empty,
+// tracing stays disabled. The returned function flushes pending spans and
+// must be called before exit.
+func Setup(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: want a URL such as http://localhost:4318", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return Install(exp, sdktrace.WithBatcher(exp)), nil
+}
+
+// Install makes a tracer provider sending spans to exp the global one and
+// returns its shutdown function. With no options spans are exported as soon
+// as they end, which suits tests using tracetest.InMemoryExporter.
+func Install(exp sdktrace.SpanExporter, opts ...sdktrace.TracerProviderOption) func(context.Context) error {
+	if len(opts) == 0 {
+		opts = []sdktrace.TracerProviderOption{sdktrace.WithSyncer(exp)}
+	}
+	opts = append(opts, sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))))
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown
+}
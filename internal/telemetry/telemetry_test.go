@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Setup(\"\") error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown error = %v", err)
+	}
+	if _, err := Setup(context.Background(), "localhost:4318"); err == nil {
+		t.Errorf("Setup should reject an invalid endpoint")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := Install(exp)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	// The in-memory exporter forgets its spans on shutdown.
+	spans := exp.GetSpans()
+	defer shutdown(context.Background())
+	if len(spans) != 1 || spans[0].Name != "op" {
+		t.Fatalf("spans = %v", spans)
+	}
+	if got, _ := spans[0].Resource.Set().Value("service.name"); got.AsString() != ServiceName {
+		t.Errorf("service.name = %q", got.AsString())
+	}
+}
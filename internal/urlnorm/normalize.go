@@ -0,0 +1,104 @@
+package urlnorm
+
+import (
+	"net/url"
+
+	"savvyshopper/domain"
+)
+
+// affiliateParams names the query parameter each brand reads a referral tag
+// from.
+var affiliateParams = map[domain.Retailer]string{
+	domain.Amazon:  "tag",
+	domain.Walmart: "wmlspartner",
+}
+
+// Normalizer puts offer URLs in canonical form, so the same listing has the
+// same URL however the retailer linked to it, and optionally adds a referral
+// tag to them. The zero Normalizer adds no tags.
+type Normalizer struct {
+	// Tags maps a marketplace's retailer, such as domain.AmazonUK, to the
+	// affiliate tag added to its offers' URLs.
+	Tags map[domain.Retailer]string
+}
+
+// Normalize is Normalizer{}.Offer: it normalizes o without adding a tag.
+func Normalize(o domain.Offer) domain.Offer {
+	return Normalizer{}.Offer(o)
+}
+
+// Offer returns o with its URL normalized and its ProductID filled in from
+// the URL if missing. An Amazon or Walmart listing whose product ID is known
+// gets the canonical URL on its marketplace, such as
+// https://www.amazon.com/dp/B0D1XD1ZV3; any other URL is cleaned of tracking
+// as Clean does. The tag for o's retailer, if any, is added last, to URLs on
+// the retailer's own site only.
+func (n Normalizer) Offer(o domain.Offer) domain.Offer {
+	if o.ProductID == "" {
+		if ref, err := ParseRef(o.URL); err == nil && ref.Retailer == o.Retailer {
+			o.ProductID = ref.ID
+		}
+	}
+	onSite := canonical(o)
+	if o.ProductID != "" && onSite {
+		o.URL = Ref{Retailer: o.Retailer, ID: o.ProductID}.URL()
+	} else if o.URL != "" {
+		o.URL = Clean(o.URL)
+	}
+	if tag := n.Tags[o.Retailer]; tag != "" && onSite && o.URL != "" {
+		o.URL = addTag(o.URL, affiliateParams[o.Retailer.Marketplace().Brand], tag)
+	}
+	return o
+}
+
+// Offers normalizes each of offers in place and returns them.
+func (n Normalizer) Offers(offers []domain.Offer) []domain.Offer {
+	for i := range offers {
+		offers[i] = n.Offer(offers[i])
+	}
+	return offers
+}
+
+// canonical reports whether o's URL can be replaced by its listing's
+// canonical one: o is on a supported marketplace and its URL, if any, is on
+// the same site rather than, say, a third-party redirect.
+func canonical(o domain.Offer) bool {
+	if _, ok := affiliateParams[o.Retailer.Marketplace().Brand]; !ok {
+		return false
+	}
+	if o.URL == "" {
+		return true
+	}
+	u, err := url.Parse(o.URL)
+	return err == nil && retailerForHost(u.Hostname()) == o.Retailer
+}
+
+// addTag sets the query parameter param of raw to tag.
+func addTag(raw, param, tag string) string {
+	u, err := url.Parse(raw)
+	if err != nil || param == "" {
+		return raw
+	}
+	q := u.Query()
+	q.Set(param, tag)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Key identifies the listing across searches, such as amazon:B0D1XD1ZV3.
+func (r Ref) Key() string {
+	return r.Retailer.Marketplace().Zinc + ":" + r.ID
+}
+
+// ProductKey identifies the listing behind o across searches and however it
+// was linked to: its marketplace and product ID when known, as Ref.Key, and
+// its URL cleaned of tracking otherwise.
+func ProductKey(o domain.Offer) string {
+	if o.ProductID != "" {
+		return Ref{Retailer: o.Retailer, ID: o.ProductID}.Key()
+	}
+	if ref, err := ParseRef(o.URL); err == nil && ref.Retailer == o.Retailer {
+		return ref.Key()
+	}
+	return Clean(o.URL)
+}
@@ -0,0 +1,66 @@
+package urlnorm
+
+import (
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestNormalizer_Offer(t *testing.T) {
+	n := Normalizer{Tags: map[domain.Retailer]string{domain.Amazon: "team-20", domain.WalmartCA: "5678"}}
+	tests := []struct {
+		name   string
+		in     domain.Offer
+		url    string
+		id     string
+		tagged bool
+	}{
+		{"amazon tracking link", domain.Offer{Retailer: domain.Amazon, URL: "https://www.amazon.com/Apple-AirPods/dp/B0BDHWDR12/ref=sr_1_1?qid=1&tag=someone-20"},
+			"https://www.amazon.com/dp/B0BDHWDR12?tag=team-20", "B0BDHWDR12", true},
+		{"id wins over sponsored path", domain.Offer{Retailer: domain.Amazon, ProductID: "B0BDHWDR12", URL: "https://amazon.com/sspa/click?ie=UTF8&spc=abc"},
+			"https://www.amazon.com/dp/B0BDHWDR12?tag=team-20", "B0BDHWDR12", true},
+		{"id without url", domain.Offer{Retailer: domain.AmazonUK, ProductID: "B0BDHWDR12"},
+			"https://www.amazon.co.uk/dp/B0BDHWDR12", "B0BDHWDR12", false},
+		{"walmart slug", domain.Offer{Retailer: domain.Walmart, URL: "https://walmart.com/ip/Apple-AirPods/1745313236?athbdg=L1600"},
+			"https://www.walmart.com/ip/1745313236", "1745313236", false},
+		{"walmart canada tag", domain.Offer{Retailer: domain.WalmartCA, ProductID: "1745313236", URL: "https://www.walmart.ca/en/ip/airpods/1745313236"},
+			"https://www.walmart.ca/en/ip/1745313236?wmlspartner=5678", "1745313236", true},
+		{"other site keeps its link", domain.Offer{Retailer: domain.Amazon, ProductID: "B0BDHWDR12", URL: "https://deals.example/r?id=9&utm_source=x"},
+			"https://deals.example/r?id=9", "B0BDHWDR12", false},
+		{"unknown retailer", domain.Offer{Retailer: "Target", ProductID: "123", URL: "https://www.target.com/p/-/A-123?utm_medium=cpc"},
+			"https://www.target.com/p/-/A-123", "123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := n.Offer(tt.in)
+			if got.URL != tt.url || got.ProductID != tt.id {
+				t.Errorf("Offer() URL, ProductID = %q, %q; want %q, %q", got.URL, got.ProductID, tt.url, tt.id)
+			}
+			if again := n.Offer(got); again.URL != got.URL {
+				t.Errorf("Offer() is not idempotent: %q, then %q", got.URL, again.URL)
+			}
+			if !tt.tagged {
+				if plain := Normalize(tt.in); plain.URL != tt.url {
+					t.Errorf("Normalize() URL = %q, want %q", plain.URL, tt.url)
+				}
+			}
+		})
+	}
+}
+
+func TestProductKey(t *testing.T) {
+	tests := []struct {
+		in   domain.Offer
+		want string
+	}{
+		{domain.Offer{Retailer: domain.Amazon, ProductID: "B0BDHWDR12", URL: "https://www.amazon.com/dp/B0BDHWDR12?tag=x"}, "amazon:B0BDHWDR12"},
+		{domain.Offer{Retailer: domain.AmazonUK, URL: "https://www.amazon.co.uk/gp/product/B0BDHWDR12?psc=1"}, "amazon_uk:B0BDHWDR12"},
+		{domain.Offer{Retailer: domain.Walmart, URL: "https://www.walmart.com/ip/AirPods/1745313236?from=/search"}, "walmart:1745313236"},
+		{domain.Offer{Retailer: domain.Amazon, URL: "https://example.com/item?id=7&gclid=abc"}, "https://example.com/item?id=7"},
+	}
+	for _, tt := range tests {
+		if got := ProductKey(tt.in); got != tt.want {
+			t.Errorf("ProductKey(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package urlnorm understands retailer product URLs and identifiers.
+package urlnorm
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"savvyshopper/domain"
+)
+
+// Ref identifies a single product listing at a retailer.
+type Ref struct {
+	Retailer domain.Retailer
+	// ID is the ASIN on Amazon or the item ID on Walmart.
+	ID string
+}
+
+var (
+	// asin matches a bare Amazon Standard Identification Number. Book ASINs
+	// are all-digit ISBN-10s, which cannot be told apart from Walmart item
+	// IDs, so a bare ASIN must contain a letter.
+	asin = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+	// walmartID matches a bare Walmart item ID.
+	walmartID = regexp.MustCompile(`^[0-9]{6,12}$`)
+	// amazonPath matches the ASIN in the common Amazon product URL shapes:
+	// /dp/ASIN, /gp/product/ASIN, /gp/aw/d/ASIN and /exec/obidos/ASIN.
+	amazonPath = regexp.MustCompile(`(?i)/(?:dp|gp/product|gp/aw/d|exec/obidos/asin|o/asin)/([a-z0-9]{10})(?:[/?]|$)`)
+	// walmartPath matches /ip/<slug>/<id> and /ip/<id>.
+	walmartPath = regexp.MustCompile(`/ip/(?:[^/]+/)?([0-9]{6,12})(?:[/?]|$)`)
+)
+
+// ParseRef accepts an Amazon or Walmart product URL from any supported
+// marketplace, a bare ASIN, or a bare Walmart item ID. Bare identifiers refer
+// to the US sites.
+func ParseRef(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	switch {
+	case asin.MatchString(upper) && strings.ContainsAny(upper, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"):
+		return Ref{Retailer: domain.Amazon, ID: upper}, nil
+	case walmartID.MatchString(s):
+		return Ref{Retailer: domain.Walmart, ID: s}, nil
+	}
+
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Ref{}, fmt.Errorf("%w: %q", domain.ErrInvalidRef, s)
+	}
+
+	retailer := retailerForHost(u.Hostname())
+	switch retailer.Marketplace().Brand {
+	case domain.Amazon:
+		if m := amazonPath.FindStringSubmatch(u.Path); m != nil {
+			return Ref{Retailer: retailer, ID: strings.ToUpper(m[1])}, nil
+		}
+	case domain.Walmart:
+		if m := walmartPath.FindStringSubmatch(u.Path); m != nil {
+			return Ref{Retailer: retailer, ID: m[1]}, nil
+		}
+	}
+	return Ref{}, fmt.Errorf("%w: %q", domain.ErrInvalidRef, s)
+}
+
+// retailerForHost maps a URL host such as "www.amazon.co.uk" or "walmart.com"
+// to its marketplace's retailer, or "" if the host is not recognized.
+func retailerForHost(host string) domain.Retailer {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	if host == "amzn.com" {
+		return domain.Amazon
+	}
+	for _, m := range domain.Marketplaces() {
+		if host == m.Domain || strings.HasSuffix(host, "."+m.Domain) {
+			return m.Retailer
+		}
+	}
+	return ""
+}
+
+// URL returns the listing's canonical URL, such as
+// https://www.amazon.com/dp/B0D1XD1ZV3 or https://www.walmart.com/ip/5689919121.
+func (r Ref) URL() string {
+	host := "https://www." + r.Retailer.Marketplace().Domain
+	switch {
+	case r.Retailer.Marketplace().Brand == domain.Amazon:
+		return host + "/dp/" + r.ID
+	case r.Retailer == domain.WalmartCA:
+		return host + "/en/ip/" + r.ID
+	}
+	return host + "/ip/" + r.ID
+}
+
+// trackingParams are query parameters that only record where a visit came
+// from; trackingPrefixes start families of them.
+var (
+	trackingParams = map[string]bool{
+		"ref": true, "ref_": true, "tag": true, "linkcode": true, "linkid": true, "camp": true,
+		"creative": true, "creativeasin": true, "ascsubtag": true, "qid": true, "sr": true,
+		"keywords": true, "crid": true, "sprefix": true, "dib": true, "dib_tag": true,
+		"content-id": true, "fbclid": true, "gclid": true, "msclkid": true, "wmlspartner": true,
+		"affiliates_ad_id": true, "campaign_id": true, "clickid": true, "veh": true, "sourceid": true,
+	}
+	trackingPrefixes = []string{"utm_", "pf_rd_", "pd_rd_", "ath"}
+)
+
+// Clean returns raw without tracking. A product URL from a supported
+// retailer becomes its canonical URL; any other URL loses its fragment and
+// tracking parameters. Anything that is not an absolute URL is returned as
+// is.
+func Clean(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	if ref, err := ParseRef(u.String()); err == nil {
+		return ref.URL()
+	}
+	q := u.Query()
+	for name := range q {
+		if tracking(name) {
+			q.Del(name)
+		}
+	}
+	u.RawQuery = q.Encode()
+	u.Fragment, u.RawFragment = "", ""
+	return u.String()
+}
+
+func tracking(param string) bool {
+	param = strings.ToLower(param)
+	if trackingParams[param] {
+		return true
+	}
+	for _, p := range trackingPrefixes {
+		if strings.HasPrefix(param, p) {
+			return true
+		}
+	}
+	return false
+}
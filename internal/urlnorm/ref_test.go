@@ -0,0 +1,72 @@
+package urlnorm
+
+import (
+	"errors"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Ref
+	}{
+		{"B0BDHWDR12", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"b0bdhwdr12", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"1745313236", Ref{domain.Walmart, "1745313236"}},
+		{"https://www.amazon.com/Apple-Generation-Cancelling-Transparency-Personalized/dp/B0BDHWDR12/ref=sr_1_1?keywords=airpods", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"https://amazon.com/gp/product/B0BDHWDR12", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"https://smile.amazon.com/dp/B0BDHWDR12?th=1", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"www.amazon.com/gp/aw/d/B0BDHWDR12", Ref{domain.Amazon, "B0BDHWDR12"}},
+		{"https://www.walmart.com/ip/Apple-AirPods-Pro-2nd-Generation/1745313236?athbdg=L1600", Ref{domain.Walmart, "1745313236"}},
+		{"https://walmart.com/ip/1745313236", Ref{domain.Walmart, "1745313236"}},
+		{"https://www.amazon.co.uk/dp/B0BDHWDR12", Ref{domain.AmazonUK, "B0BDHWDR12"}},
+		{"https://www.amazon.de/-/en/dp/B0BDHWDR12", Ref{domain.AmazonDE, "B0BDHWDR12"}},
+		{"https://www.amazon.co.jp/gp/product/B0BDHWDR12", Ref{domain.AmazonJP, "B0BDHWDR12"}},
+		{"https://www.walmart.ca/en/ip/1745313236", Ref{domain.WalmartCA, "1745313236"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRef(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRef() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRef() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRef_Invalid(t *testing.T) {
+	for _, in := range []string{"", "airpods", "https://www.target.com/p/-/A-85978612", "https://www.amazon.com/s?k=airpods"} {
+		if _, err := ParseRef(in); !errors.Is(err, domain.ErrInvalidRef) {
+			t.Errorf("ParseRef(%q) error = %v, want ErrInvalidRef", in, err)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://www.amazon.com/Apple-Generation-Cancelling-Transparency-Personalized/dp/B0BDHWDR12/ref=sr_1_1?keywords=airpods&qid=1700000000&sr=8-1&tag=someone-20",
+			"https://www.amazon.com/dp/B0BDHWDR12"},
+		{"https://smile.amazon.com/gp/product/b0bdhwdr12?th=1", "https://www.amazon.com/dp/B0BDHWDR12"},
+		{"https://www.amazon.co.uk/dp/B0BDHWDR12?utm_source=mail#reviews", "https://www.amazon.co.uk/dp/B0BDHWDR12"},
+		{"https://www.walmart.com/ip/Apple-AirPods-Pro-2nd-Generation/1745313236?athbdg=L1600&from=/search", "https://www.walmart.com/ip/1745313236"},
+		{"https://www.walmart.ca/en/ip/apple-airpods/1745313236?utm_medium=cpc", "https://www.walmart.ca/en/ip/1745313236"},
+		// Other URLs keep everything but tracking.
+		{"https://www.amazon.com/s?k=airpods&ref=nb_sb_noss&utm_campaign=x", "https://www.amazon.com/s?k=airpods"},
+		{"https://example.com/item?id=7&gclid=abc&fbclid=def#top", "https://example.com/item?id=7"},
+		{"not a url", "not a url"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.in); got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
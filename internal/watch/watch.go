@@ -0,0 +1,133 @@
+// Package watch keeps the list of listings a shopper is watching, whose
+// prices are refreshed into the price history and forecast.
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/history"
+)
+
+// ErrUnknown means no watch has the given ID.
+var ErrUnknown = errors.New("no such watch")
+
+// Watch is a watched listing.
+type Watch struct {
+	// ID is the short number the shopper refers to the watch by.
+	ID        string          `json:"id"`
+	Retailer  domain.Retailer `json:"retailer"`
+	ProductID string          `json:"product_id"`
+	Title     string          `json:"title,omitempty"`
+	URL       string          `json:"url,omitempty"`
+	Added     time.Time       `json:"added"`
+}
+
+// Key returns the listing's key in the price history.
+func (w Watch) Key() string {
+	return history.ProductKey(w.Retailer, w.ProductID)
+}
+
+// PathFor returns the watch list kept beside the price history at
+// historyPath.
+func PathFor(historyPath string) string {
+	return filepath.Join(filepath.Dir(historyPath), "watches.json")
+}
+
+// List is the watch list, backed by a JSON file.
+type List struct {
+	path    string
+	watches []Watch
+}
+
+// Open reads the watch list at path. A missing file is an empty list; it is
+// created on the first Save.
+func Open(path string) (*List, error) {
+	l := &List{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch list: %w", err)
+	}
+	if err := json.Unmarshal(data, &l.watches); err != nil {
+		return nil, fmt.Errorf("failed to parse watch list %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Watches returns the watches in the order they were added.
+func (l *List) Watches() []Watch {
+	return append([]Watch(nil), l.watches...)
+}
+
+// Get returns the watch with id.
+func (l *List) Get(id string) (Watch, error) {
+	for _, w := range l.watches {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return Watch{}, fmt.Errorf("%w %q; see savvyshopper watch list", ErrUnknown, id)
+}
+
+// Add watches w, giving it the next free ID, and returns it. A listing that
+// is already watched keeps its watch, with its title and URL updated.
+func (l *List) Add(w Watch) Watch {
+	next := 1
+	for i, existing := range l.watches {
+		if existing.Key() == w.Key() {
+			if w.Title != "" {
+				l.watches[i].Title = w.Title
+			}
+			if w.URL != "" {
+				l.watches[i].URL = w.URL
+			}
+			return l.watches[i]
+		}
+		if n, err := strconv.Atoi(existing.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	w.ID = strconv.Itoa(next)
+	l.watches = append(l.watches, w)
+	return w
+}
+
+// Remove stops watching id.
+func (l *List) Remove(id string) error {
+	for i, w := range l.watches {
+		if w.ID == id {
+			l.watches = append(l.watches[:i], l.watches[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q; see savvyshopper watch list", ErrUnknown, id)
+}
+
+// Save writes the list back to its file, replacing it whole so a failed
+// write never leaves it truncated.
+func (l *List) Save() error {
+	data, err := json.MarshalIndent(l.watches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watch list: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write watch list: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write watch list: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write watch list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package watch
+
+import (
+	"path/filepath"
+	"testing"
+
+	"savvyshopper/domain"
+)
+
+func TestList_AddRemoveSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watches.json")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	airpods := l.Add(Watch{Retailer: domain.Amazon, ProductID: "B0D1XD1ZV3"})
+	tv := l.Add(Watch{Retailer: domain.Walmart, ProductID: "5689919121", Title: "TV"})
+	again := l.Add(Watch{Retailer: domain.Amazon, ProductID: "B0D1XD1ZV3", Title: "AirPods Pro 2"})
+	if airpods.ID != "1" || tv.ID != "2" || again.ID != "1" || again.Title != "AirPods Pro 2" {
+		t.Errorf("IDs = %s, %s, %s; title %q", airpods.ID, tv.ID, again.ID, again.Title)
+	}
+	if airpods.Key() != "amazon:B0D1XD1ZV3" {
+		t.Errorf("Key() = %q", airpods.Key())
+	}
+
+	if err := l.Remove("1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := l.Remove("1"); err == nil {
+		t.Error("Remove() of a removed watch error = nil, want error")
+	}
+	if w := l.Add(Watch{Retailer: domain.AmazonUK, ProductID: "B0D1XD1ZV3"}); w.ID != "3" {
+		t.Errorf("ID after removal = %s, want 3", w.ID)
+	}
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := l.Watches(); len(got) != 2 || got[0].ID != "2" || got[1].Key() != "amazon_uk:B0D1XD1ZV3" {
+		t.Errorf("Watches() = %+v", got)
+	}
+	if _, err := l.Get("2"); err != nil {
+		t.Errorf("Get(2) error = %v", err)
+	}
+	if _, err := l.Get("9"); err == nil {
+		t.Error("Get(9) error = nil, want error")
+	}
+}
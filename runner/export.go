@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"savvyshopper/internal/render"
+	"savvyshopper/internal/report"
+)
+
+// reportWriters maps each export format to its renderer.
+var reportWriters = map[string]func(io.Writer, report.Report) error{
+	"csv":  render.CSVReport,
+	"json": render.JSONReport,
+	"html": render.HTMLReport,
+}
+
+// runExport writes a per-product report of the price history between --from
+// and --to.
+func runExport(ctx context.Context, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fromFlag := fs.String("from", "", "first day to include, as YYYY-MM-DD (default: the start of the history)")
+	toFlag := fs.String("to", "", "last day to include, as YYYY-MM-DD (default: today)")
+	format := fs.String("format", "csv", "report format: csv|json|html")
+	output := fs.String("output", "", "write the report to this file instead of stdout")
+	fs.StringVar(output, "o", "", "shorthand for --output")
+	var log logFlags
+	log.register(fs)
+	var cfgFlags configFlags
+	cfgFlags.register(fs)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if _, err = log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	conf, err := cfgFlags.load(w)
+	if err != nil {
+		return err
+	}
+
+	write, ok := reportWriters[*format]
+	if !ok {
+		err = fmt.Errorf("invalid --format %q: want csv, json or html", *format)
+	} else if len(args) > 0 {
+		err = errors.New("usage: savvyshopper export [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--format csv|json|html] [--output FILE]")
+	} else if conf.history == nil {
+		err = errors.New("nothing to export with --no-history")
+	}
+	var from, to time.Time
+	if err == nil {
+		from, to, err = parseDateRange(*fromFlag, *toFlag)
+	}
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+
+	// Points are kept up to the end of the --to day.
+	r := report.Build(from, to, conf.history.Between(from, to.AddDate(0, 0, 1)))
+	if *output == "" {
+		return write(w, r)
+	}
+	f, err := os.Create(*output)
+	if err == nil {
+		err = write(f, r)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to write report: %w", err)
+		reportError(w, err)
+	}
+	return err
+}
+
+// parseDateRange parses the --from and --to days, in UTC. An empty from
+// leaves the start open; an empty to means today.
+func parseDateRange(fromS, toS string) (from, to time.Time, err error) {
+	if fromS != "" {
+		if from, err = time.Parse(time.DateOnly, fromS); err != nil {
+			return from, to, fmt.Errorf("invalid --from %q: want YYYY-MM-DD", fromS)
+		}
+	}
+	to = time.Now().UTC().Truncate(24 * time.Hour)
+	if toS != "" {
+		if to, err = time.Parse(time.DateOnly, toS); err != nil {
+			return from, to, fmt.Errorf("invalid --to %q: want YYYY-MM-DD", toS)
+		}
+	}
+	if !from.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("--to %s is before --from %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	return from, to, nil
+}
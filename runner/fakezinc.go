@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"savvyshopper/internal/fakezinc"
+)
+
+// runFakeZinc serves a fake Zinc API from seed data until ctx is cancelled.
+// Point the other commands at it with ZINC_BASE_URL.
+func runFakeZinc(ctx context.Context, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("fakezinc", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addr := fs.String("addr", "localhost:8090", "address to listen on")
+	dataDir := fs.String("data", "", "seed data directory (default: built-in catalog)")
+	var opts fakezinc.Options
+	fs.DurationVar(&opts.Latency, "latency", 0, "delay every response by this long")
+	fs.Float64Var(&opts.ErrorRate, "error-rate", 0, "fraction of requests answered with 500 (0-1)")
+	fs.Float64Var(&opts.RateLimitRate, "rate-limit-rate", 0, "fraction of requests answered with 429 (0-1)")
+	fs.Float64Var(&opts.MalformedRate, "malformed-rate", 0, "fraction of responses with truncated JSON (0-1)")
+	fs.Int64Var(&opts.Seed, "seed", 0, "random seed for the failure rates (0: random)")
+	fs.StringVar(&opts.APIKey, "api-key", "", "require this API key as the Basic Auth username")
+	fs.IntVar(&opts.PollsUntilReady, "polls", 1, "times an async request reports processing before its result")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	for name, rate := range map[string]float64{"error-rate": opts.ErrorRate, "rate-limit-rate": opts.RateLimitRate, "malformed-rate": opts.MalformedRate} {
+		if rate < 0 || rate > 1 {
+			err := fmt.Errorf("invalid --%s %v: want a fraction between 0 and 1", name, rate)
+			reportError(w, err)
+			return err
+		}
+	}
+
+	catalog := fakezinc.DefaultCatalog()
+	if *dataDir != "" {
+		var err error
+		if catalog, err = fakezinc.LoadDir(*dataDir); err != nil {
+			reportError(w, err)
+			return err
+		}
+	}
+
+	srv := &http.Server{Addr: *addr, Handler: fakezinc.New(catalog, opts)}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+	fmt.Fprintf(w, "Fake Zinc listening on %s\nexport ZINC_BASE_URL=http://%s\n", *addr, *addr)
+
+	select {
+	case err := <-errCh:
+		reportError(w, err)
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
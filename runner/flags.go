@@ -0,0 +1,475 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/deal"
+	"savvyshopper/internal/history"
+	"savvyshopper/internal/launch"
+	"savvyshopper/internal/logging"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/promo"
+	"savvyshopper/internal/render"
+	"savvyshopper/internal/tax"
+)
+
+// options holds the flags shared by the search commands.
+type options struct {
+	verbose      bool
+	stream       bool
+	minRelevance float64
+	exclude      stringList
+	metricsFile  string
+	otlpEndpoint string
+	log          logFlags
+	config       configFlags
+	deals        bool
+	// format is how search results are written: a table, Markdown or HTML.
+	format       string
+	htmlTemplate *template.Template
+	// template replaces the table with the --template output.
+	template *texttemplate.Template
+	urls     render.URLMode
+	// profile and history are set from the loaded settings.
+	profile promo.Profile
+	history *history.Store
+	// tax estimates sales tax for --zip; nil without it.
+	tax    *tax.Estimator
+	refine price.Refinement
+	// open and copy number the offer --open and --copy pick, from 1 as
+	// listed; 0 picks none.
+	open, copy int
+}
+
+// apply works out the shopper's savings, estimates tax if --zip was given,
+// rates each deal, then applies the filter and sort flags, so sorting by price
+// or landed cost uses what the shopper would actually pay.
+func (opts options) apply(offers []domain.Offer) []domain.Offer {
+	offers = opts.profile.Apply(offers)
+	if opts.tax != nil {
+		offers = opts.tax.Apply(offers)
+	}
+	offers = deal.Scorer{History: opts.history}.Score(offers)
+	return opts.refine.Apply(offers)
+}
+
+// record adds offers to the price history, if one is kept. A failure is
+// logged rather than returned, since the search itself succeeded.
+func (opts options) record(ctx context.Context, offers []domain.Offer) {
+	if opts.history == nil {
+		return
+	}
+	if err := opts.history.Record(offers, time.Now()); err != nil {
+		logging.FromContext(ctx).Warn("failed to record prices", "error", err)
+	}
+}
+
+// tableOptions returns the table columns the flags ask for, fitted to w.
+func (opts options) tableOptions(w io.Writer) render.TableOptions {
+	return render.TableOptions{
+		Verbose:   opts.verbose,
+		Landed:    opts.tax != nil,
+		Discounts: !opts.profile.IsZero(),
+		Deals:     opts.deals || opts.refine.Sort == price.SortDeal,
+		URLs:      opts.urls,
+		Terminal:  render.DetectTerminal(w),
+	}
+}
+
+// Output formats for --format.
+const (
+	formatTable    = "table"
+	formatMarkdown = "md"
+	formatHTML     = "html"
+)
+
+// render writes the search results for query with --template or in the
+// format --format asks for.
+func (opts options) render(w io.Writer, query string, res price.SearchResult) error {
+	switch {
+	case opts.template != nil:
+		return render.Template(w, opts.template, render.NewView(query, res))
+	case opts.format == formatMarkdown:
+		return render.Markdown(w, res.Offers, opts.tableOptions(w))
+	case opts.format == formatHTML:
+		return render.HTML(w, res.Offers, render.HTMLOptions{
+			TableOptions: opts.tableOptions(w),
+			Title:        "Prices for " + query,
+			Template:     opts.htmlTemplate,
+		})
+	}
+	return render.Table(w, res.Offers, opts.tableOptions(w))
+}
+
+// tableOnly fails if --format or --template asked for anything but the
+// table, or --open or --copy for an offer, for commands with their own
+// layout.
+func (opts options) tableOnly(name string) error {
+	if opts.open > 0 || opts.copy > 0 {
+		return fmt.Errorf("%s does not support --open or --copy", name)
+	}
+	if opts.format != formatTable {
+		return fmt.Errorf("%s does not support --format %s", name, opts.format)
+	}
+	if opts.template != nil {
+		return fmt.Errorf("%s does not support --template", name)
+	}
+	return nil
+}
+
+// logFlags are the logging flags every command accepts. Logs go to stderr so
+// stdout only carries results.
+type logFlags struct {
+	level  string
+	format string
+	debug  bool
+}
+
+// register adds the logging flags to fs.
+func (l *logFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&l.level, "log-level", "warn", "minimum level of log messages: debug|info|warn|error")
+	fs.StringVar(&l.format, "log-format", logging.FormatText, "log message format: text|json")
+	fs.BoolVar(&l.debug, "debug", false, "shorthand for --log-level debug")
+}
+
+// withLogger returns ctx carrying the logger the flags describe, printing any
+// invalid value to errOut.
+func (l logFlags) withLogger(ctx context.Context, errOut io.Writer) (context.Context, error) {
+	level, err := logging.ParseLevel(l.level)
+	if err == nil && l.debug {
+		level = slog.LevelDebug
+	}
+	var logger *slog.Logger
+	if err == nil {
+		logger, err = logging.New(os.Stderr, level, l.format)
+	}
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return ctx, err
+	}
+	return logging.WithContext(ctx, logger), nil
+}
+
+// configFlags are the flags that override the settings from the config file
+// and environment.
+type configFlags struct {
+	path          string
+	baseURL       string
+	paths         stringList
+	markets       stringList
+	proxy         string
+	caFile        string
+	tags          stringList
+	members       stringList
+	subscribeSave bool
+	history       string
+	noHistory     bool
+	opener        string
+}
+
+// register adds the settings flags to fs.
+func (c *configFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.path, "config", "", "read settings from this JSON file (default $SAVVY_CONFIG or savvyshopper/config.json in the user config directory)")
+	fs.StringVar(&c.baseURL, "zinc-url", "", "root of the Zinc API (default https://api.zinc.io/v1)")
+	fs.Var(&c.paths, "zinc-path", "search endpoint for a marketplace as zinc-code=/path, e.g. amazon_uk=/search/amazon_uk (repeatable, comma-separated)")
+	fs.Var(&c.markets, "marketplace", "search these sites, e.g. amazon.co.uk,amazon.de (repeatable, comma-separated; default amazon.com,walmart.com)")
+	fs.StringVar(&c.proxy, "proxy", "", "send Zinc requests through this HTTP proxy")
+	fs.StringVar(&c.caFile, "ca-file", "", "also trust the certificate authorities in this PEM file")
+	fs.Var(&c.tags, "affiliate-tag", "add a referral tag to a marketplace's offer URLs as marketplace=tag, e.g. amazon.com=team-20 (repeatable, comma-separated)")
+	fs.Var(&c.members, "member", "count member prices for these programs: prime|walmart_plus (repeatable, comma-separated)")
+	fs.BoolVar(&c.subscribeSave, "subscribe-save", false, "count subscribe-and-save discounts")
+	fs.StringVar(&c.history, "history", "", "record prices to and rate deals against this file (default $SAVVY_HISTORY or savvyshopper/history.jsonl in the user config directory)")
+	fs.BoolVar(&c.noHistory, "no-history", false, "neither record prices nor rate deals against past prices")
+	fs.StringVar(&c.opener, "opener", "", "open offers with this command, given the URL as its last argument (default $SAVVY_OPENER or the system browser)")
+}
+
+// settings are the validated Zinc settings, the client to reach Zinc with,
+// the shopper's profile, the price history, nil if not kept, and what opens
+// offers.
+type settings struct {
+	zinc    config.Zinc
+	client  *http.Client
+	profile promo.Profile
+	history *history.Store
+	opener  launch.Opener
+}
+
+// load resolves the settings from the config file, the environment and the
+// flags, in increasing precedence, and validates them, reporting any problem
+// to w.
+func (c configFlags) load(w io.Writer) (settings, error) {
+	conf, err := c.resolve()
+	if err != nil {
+		reportError(w, err)
+		return settings{}, err
+	}
+	return conf, nil
+}
+
+func (c configFlags) resolve() (settings, error) {
+	cfg, err := config.Load(c.path)
+	if err != nil {
+		return settings{}, err
+	}
+	over := config.Zinc{BaseURL: c.baseURL, Proxy: c.proxy, CAFile: c.caFile, Marketplaces: c.markets, Paths: make(map[string]string)}
+	for _, p := range c.paths {
+		retailer, path, ok := strings.Cut(p, "=")
+		if !ok {
+			return settings{}, fmt.Errorf("invalid --zinc-path %q: want zinc-code=/path", p)
+		}
+		over.Paths[strings.TrimSpace(retailer)] = strings.TrimSpace(path)
+	}
+	for _, t := range c.tags {
+		market, tag, ok := strings.Cut(t, "=")
+		if !ok || strings.TrimSpace(tag) == "" {
+			return settings{}, fmt.Errorf("invalid --affiliate-tag %q: want marketplace=tag", t)
+		}
+		if over.AffiliateTags == nil {
+			over.AffiliateTags = make(map[string]string)
+		}
+		over.AffiliateTags[strings.TrimSpace(market)] = strings.TrimSpace(tag)
+	}
+	conf := settings{zinc: cfg.Zinc.Merge(over)}
+	if err := conf.zinc.Validate(); err != nil {
+		return settings{}, err
+	}
+	if conf.client, err = conf.zinc.HTTPClient(); err != nil {
+		return settings{}, err
+	}
+
+	members := cfg.Profile.Memberships
+	if len(c.members) > 0 {
+		members = c.members
+	}
+	if conf.profile, err = promo.NewProfile(members, cfg.Profile.SubscribeSave || c.subscribeSave); err != nil {
+		return settings{}, err
+	}
+	if conf.history, err = c.openHistory(cfg.History); err != nil {
+		return settings{}, err
+	}
+	opener := c.opener
+	if opener == "" {
+		opener = cfg.Opener
+	}
+	conf.opener = launch.ParseCommand(opener)
+	return conf, nil
+}
+
+// openHistory opens the price history named by --history, or else by the
+// config file or SAVVY_HISTORY, or else the default one. It returns nil with
+// --no-history, or if there is no default location.
+func (c configFlags) openHistory(configured string) (*history.Store, error) {
+	if c.noHistory {
+		return nil, nil
+	}
+	path := c.history
+	if path == "" {
+		path = configured
+	}
+	if path == "" {
+		var err error
+		if path, err = history.DefaultPath(); err != nil {
+			return nil, nil
+		}
+	}
+	return history.Open(path)
+}
+
+// searchers returns searchersOpt[0] if provided, and otherwise the Zinc
+// searchers for s.
+func (s settings) searchers(searchersOpt ...map[domain.Retailer]price.Searcher) map[domain.Retailer]price.Searcher {
+	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
+		return searchersOpt[0]
+	}
+	return price.NewSearchers(s.zinc, s.client)
+}
+
+// products returns a product client for s.
+func (s settings) products() *price.ProductClient {
+	c := price.NewProductClient(s.zinc.BaseURL, s.client)
+	c.Tags = s.zinc.Tags()
+	return c
+}
+
+// stringList is a flag that may be repeated or given a comma-separated list.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// parseFlags parses args for the named command. Flags may appear before or
+// after the query; the remaining positional arguments are returned.
+func parseFlags(name string, args []string, errOut io.Writer) (options, []string, error) {
+	var opts options
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.BoolVar(&opts.verbose, "verbose", false, "show extra columns such as relevance")
+	fs.BoolVar(&opts.verbose, "v", false, "shorthand for --verbose")
+	fs.BoolVar(&opts.stream, "stream", false, "print each retailer's offers as soon as they arrive")
+	fs.StringVar(&opts.format, "format", formatTable, "write results as a table, Markdown or a standalone HTML page: table|md|html")
+	htmlTemplate := fs.String("html-template", "", "render --format html with this template file, which may redefine the built-in blocks")
+	outputTemplate := fs.String("template", "", "write results with this Go text/template instead of a table")
+	templateFile := fs.String("template-file", "", "write results with the Go text/template in this file")
+	urls := fs.String("urls", "full", "show offer URLs in tables in full, shortened to host and path, or not at all: full|short|none")
+	fs.BoolVar(&opts.deals, "deals", false, "show each offer's deal score and whether to buy now or wait")
+	fs.Float64Var(&opts.minRelevance, "min-relevance", 0, "drop offers scoring below this relevance (0-1)")
+	fs.Var(&opts.exclude, "exclude", "drop offers whose title contains this keyword (repeatable, comma-separated)")
+	sortKey := fs.String("sort", "price", "order by price|landed|rating|reviews|relevance|retailer|deal")
+	fs.BoolVar(&opts.refine.Desc, "desc", false, "sort in descending order")
+	fs.Float64Var(&opts.refine.MinPrice, "min-price", 0, "drop offers cheaper than this")
+	fs.Float64Var(&opts.refine.MaxPrice, "max-price", 0, "drop offers more expensive than this")
+	condition := fs.String("condition", "", "keep only offers in this condition: new|used|refurbished")
+	fs.BoolVar(&opts.refine.InStock, "in-stock", false, "drop offers reported out of stock")
+	fs.BoolVar(&opts.refine.PrimeOnly, "prime-only", false, "keep only Prime-eligible offers")
+	fs.Float64Var(&opts.refine.MinRating, "min-rating", 0, "drop offers rated below this many stars")
+	fulfillment := fs.String("fulfillment", "", "keep only offers shipped by the retailer or the seller: retailer|seller")
+	zip := fs.String("zip", "", "estimate sales tax for delivery to this US ZIP code and show landed cost")
+	taxRates := fs.String("tax-rates", "", "override the bundled sales tax rates with this JSON file")
+	fs.IntVar(&opts.open, "open", 0, "open the Nth offer listed in the browser")
+	fs.IntVar(&opts.copy, "copy", 0, "copy the Nth offer's URL to the clipboard through the terminal")
+	fs.StringVar(&opts.metricsFile, "metrics-file", "", "write Prometheus metrics for the run to this file")
+	fs.StringVar(&opts.otlpEndpoint, "otlp-endpoint", "", "export traces over OTLP/HTTP to this URL, e.g. http://localhost:4318")
+	opts.log.register(fs)
+	opts.config.register(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return opts, nil, err
+	}
+
+	if opts.refine.Sort, err = price.ParseSortKey(*sortKey); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.refine.Condition, err = price.ParseCondition(*condition); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.refine.Fulfillment, err = price.ParseFulfillment(*fulfillment); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.urls, err = render.ParseURLMode(*urls); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.tax, err = newTaxEstimator(*zip, *taxRates); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.htmlTemplate, err = parseFormat(opts.format, *htmlTemplate, opts.stream); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if opts.template, err = parseTemplate(*outputTemplate, *templateFile, opts.format, opts.stream); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	if err = checkPicks(opts.open, opts.copy, opts.stream); err != nil {
+		fmt.Fprintln(errOut, err)
+		return opts, nil, err
+	}
+	return opts, positional, nil
+}
+
+// parseFormat checks --format and loads --html-template, if given.
+func parseFormat(format, templateFile string, stream bool) (*template.Template, error) {
+	switch {
+	case format != formatTable && format != formatMarkdown && format != formatHTML:
+		return nil, fmt.Errorf("invalid --format %q: want table, md or html", format)
+	case stream && format != formatTable:
+		return nil, errors.New("--stream only prints tables")
+	case templateFile == "":
+		return nil, nil
+	case format != formatHTML:
+		return nil, errors.New("--html-template needs --format html")
+	}
+	return render.LoadHTMLTemplate(templateFile)
+}
+
+// parseTemplate parses --template or --template-file, if either was given.
+func parseTemplate(text, file, format string, stream bool) (*texttemplate.Template, error) {
+	switch {
+	case text == "" && file == "":
+		return nil, nil
+	case text != "" && file != "":
+		return nil, errors.New("use --template or --template-file, not both")
+	case format != formatTable:
+		return nil, fmt.Errorf("--template replaces --format %s", format)
+	case stream:
+		return nil, errors.New("--stream only prints tables")
+	case file != "":
+		return render.ParseTemplateFile(file)
+	}
+	return render.ParseTemplate(text)
+}
+
+// checkPicks checks the offer numbers given to --open and --copy.
+func checkPicks(openN, copyN int, stream bool) error {
+	switch {
+	case openN < 0:
+		return fmt.Errorf("invalid --open %d: offers are numbered from 1", openN)
+	case copyN < 0:
+		return fmt.Errorf("invalid --copy %d: offers are numbered from 1", copyN)
+	case stream && (openN > 0 || copyN > 0):
+		return errors.New("--open and --copy need the sorted results, not --stream")
+	}
+	return nil
+}
+
+// newTaxEstimator returns the estimator for --zip and --tax-rates, or nil if
+// no ZIP code was given.
+func newTaxEstimator(zip, ratesFile string) (*tax.Estimator, error) {
+	if zip == "" {
+		if ratesFile != "" {
+			return nil, errors.New("--tax-rates needs --zip")
+		}
+		return nil, nil
+	}
+	var table *tax.Table
+	if ratesFile != "" {
+		var err error
+		if table, err = tax.Load(ratesFile); err != nil {
+			return nil, err
+		}
+	}
+	return tax.NewEstimator(zip, table)
+}
+
+// parseArgs parses args with fs, allowing flags before or after positional
+// arguments, and returns the positional ones.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
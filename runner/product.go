@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/match"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/render"
+	"savvyshopper/internal/urlnorm"
+)
+
+// runProduct looks up a single listing by URL, ASIN or Walmart item ID and,
+// with --cross, searches the other retailers for the same product.
+func runProduct(ctx context.Context, args []string, w io.Writer, searchersOpt ...map[domain.Retailer]price.Searcher) error {
+	fs := flag.NewFlagSet("product", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	cross := fs.Bool("cross", false, "also search the other retailers for the same product")
+	var log logFlags
+	log.register(fs)
+	var cfgFlags configFlags
+	cfgFlags.register(fs)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if ctx, err = log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	conf, err := cfgFlags.load(w)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		err := errors.New("usage: savvyshopper product <url|ASIN|item-id>")
+		reportError(w, err)
+		return err
+	}
+
+	ref, err := urlnorm.ParseRef(args[0])
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	if _, err := config.APIKey(); err != nil {
+		reportError(w, err)
+		return err
+	}
+
+	details, err := conf.products().Lookup(ctx, ref.Retailer, ref.ID)
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	if err := render.Product(w, details); err != nil {
+		return err
+	}
+	if !*cross {
+		return nil
+	}
+
+	// Other marketplaces of the same retailer, such as amazon.co.uk for an
+	// amazon.com listing, are not other retailers.
+	brand := details.Retailer.Marketplace().Brand
+	others := make(map[domain.Retailer]price.Searcher)
+	for r, s := range conf.searchers(searchersOpt...) {
+		if r.Marketplace().Brand != brand {
+			others[r] = s
+		}
+	}
+	fmt.Fprintln(w, "\nOther retailers:")
+	offers, err := price.SearchPrices(ctx, details.Title, others)
+	if err == nil {
+		offers = sameProduct(details, offers)
+		if len(offers) == 0 {
+			err = domain.ErrNoResults
+		}
+	}
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	return render.Table(w, offers)
+}
+
+// runOffers lists every seller's offer for one or more listings, grouped by
+// listing, after applying the filter and sort flags.
+func runOffers(ctx context.Context, args []string, w io.Writer) error {
+	opts, args, err := parseFlags("offers", args, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if err = opts.tableOnly("offers"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if ctx, err = opts.log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	conf, err := opts.config.load(w)
+	if err != nil {
+		return err
+	}
+	opts.profile = conf.profile
+	if len(args) == 0 {
+		err := errors.New("usage: savvyshopper offers <url|ASIN|item-id>...")
+		reportError(w, err)
+		return err
+	}
+	if _, err := config.APIKey(); err != nil {
+		reportError(w, err)
+		return err
+	}
+
+	client := conf.products()
+	products := make([]domain.ProductDetails, 0, len(args))
+	for _, arg := range args {
+		ref, err := urlnorm.ParseRef(arg)
+		if err != nil {
+			reportError(w, err)
+			return err
+		}
+		details, err := client.Lookup(ctx, ref.Retailer, ref.ID)
+		if err != nil {
+			reportError(w, err)
+			return err
+		}
+		details.Offers = opts.apply(details.Offers)
+		products = append(products, details)
+	}
+	return render.Sellers(w, products...)
+}
+
+// sameProduct returns the offers that match the looked-up listing.
+func sameProduct(details domain.ProductDetails, offers []domain.Offer) []domain.Offer {
+	clusters := match.Group(append([]domain.Offer{details.Offer()}, offers...))
+	return clusters[0].Offers[1:]
+}
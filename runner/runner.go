@@ -4,50 +4,270 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 
 	"savvyshopper/domain"
 	"savvyshopper/internal/config"
+	"savvyshopper/internal/logging"
+	"savvyshopper/internal/match"
 	"savvyshopper/internal/price"
+	"savvyshopper/internal/relevance"
 	"savvyshopper/internal/render"
+	"savvyshopper/internal/telemetry"
 )
 
-// Run executes the CLI logic.
+// Run executes the CLI logic. A first argument naming a subcommand runs it;
+// anything else is a search, as is "search" followed by a query that would
+// otherwise name a subcommand, such as "watch".
 // If searchersOpt is provided, it uses those searchers instead of the default ones.
 func Run(ctx context.Context, args []string, w io.Writer, searchersOpt ...map[domain.Retailer]price.Searcher) error {
-	var query string
 	if len(args) > 0 {
-		query = args[0]
+		switch args[0] {
+		case "search":
+			args = args[1:]
+		case "compare":
+			return runCompare(ctx, args[1:], w, searchersOpt...)
+		case "product":
+			return runProduct(ctx, args[1:], w, searchersOpt...)
+		case "offers":
+			return runOffers(ctx, args[1:], w)
+		case "serve":
+			return runServe(ctx, args[1:], w, searchersOpt...)
+		case "fakezinc":
+			return runFakeZinc(ctx, args[1:], w)
+		case "watch":
+			return runWatch(ctx, args[1:], w)
+		case "forecast":
+			return runForecast(ctx, args[1:], w)
+		case "export":
+			return runExport(ctx, args[1:], w)
+		}
+	}
+
+	opts, args, err := parseFlags("savvyshopper", args, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if ctx, err = opts.log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	shutdown, err := startTracing(ctx, opts.otlpEndpoint, w)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+	conf, err := opts.config.load(w)
+	if err != nil {
+		return err
+	}
+	opts.profile, opts.history = conf.profile, conf.history
+	searchers := conf.searchers(searchersOpt...)
+	if opts.stream {
+		err = streamSearch(ctx, args, w, opts, searchers)
 	} else {
-		fmt.Fprint(w, "Enter product: ")
-		if _, err := fmt.Fscanln(os.Stdin, &query); err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
+		var query string
+		var res price.SearchResult
+		if query, res, err = search(ctx, args, w, opts, searchers); err == nil {
+			err = opts.render(w, query, res)
+		}
+		if err == nil {
+			err = opts.share(ctx, w, conf.opener, res.Offers)
 		}
 	}
+	return writeMetricsFile(opts.metricsFile, err)
+}
 
-	_, err := config.APIKey()
+// runCompare groups the search results into products and shows each
+// retailer's price side by side.
+func runCompare(ctx context.Context, args []string, w io.Writer, searchersOpt ...map[domain.Retailer]price.Searcher) error {
+	opts, args, err := parseFlags("compare", args, os.Stderr)
 	if err != nil {
-		fmt.Fprintf(w, "\033[31mError: %v\033[0m\n", err)
 		return err
 	}
+	if err = opts.tableOnly("compare"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if ctx, err = opts.log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	shutdown, err := startTracing(ctx, opts.otlpEndpoint, w)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+	conf, err := opts.config.load(w)
+	if err != nil {
+		return err
+	}
+	opts.profile, opts.history = conf.profile, conf.history
+	_, res, err := search(ctx, args, w, opts, conf.searchers(searchersOpt...))
+	if err == nil {
+		err = render.Compare(w, match.Group(res.Offers))
+	}
+	return writeMetricsFile(opts.metricsFile, err)
+}
 
-	var offers []domain.Offer
-	if len(searchersOpt) > 0 && searchersOpt[0] != nil {
-		offers, err = price.SearchPrices(ctx, query, searchersOpt[0])
-	} else {
-		offers, err = price.SearchPrices(ctx, query)
+// startTracing exports traces to endpoint, if set, and returns a function that
+// flushes them.
+func startTracing(ctx context.Context, endpoint string, w io.Writer) (func(), error) {
+	shutdown, err := telemetry.Setup(ctx, endpoint)
+	if err != nil {
+		reportError(w, err)
+		return nil, err
+	}
+	return func() {
+		// Flush even if ctx was cancelled by an interrupt.
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		shutdown(ctx)
+	}, nil
+}
+
+// writeMetricsFile dumps the search metrics to path, if set, whether or not
+// the command succeeded, and returns the command's error runErr in preference
+// to its own.
+func writeMetricsFile(path string, runErr error) error {
+	if path == "" {
+		return runErr
+	}
+	f, err := os.Create(path)
+	if err == nil {
+		err = price.WriteMetrics(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
 	}
+	if runErr != nil {
+		return runErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// search reads the query, checks the API key, runs the price search, drops
+// irrelevant offers and applies the filter and sort flags, reporting failures
+// to w. It returns the query with the result, whose offers are refined.
+func search(ctx context.Context, args []string, w io.Writer, opts options, searchers map[domain.Retailer]price.Searcher) (string, price.SearchResult, error) {
+	if err := opts.refine.Check(slices.Collect(maps.Keys(searchers))); err != nil {
+		reportError(w, err)
+		return "", price.SearchResult{}, err
+	}
+	query, err := readQuery(args, w)
 	if err != nil {
-		switch err {
-		case domain.ErrNoResults:
-			fmt.Fprintf(w, "\033[33mNo results found.\033[0m\n")
-		case domain.ErrNetwork:
-			fmt.Fprintf(w, "\033[31mNetwork error: %v\033[0m\n", err)
-		default:
-			fmt.Fprintf(w, "\033[31mError: %v\033[0m\n", err)
+		return "", price.SearchResult{}, err
+	}
+
+	res, err := price.Search(ctx, query, searchers)
+	if err == nil {
+		if res.Offers = refine(ctx, query, res.Offers, opts); len(res.Offers) == 0 {
+			err = domain.ErrNoResults
+		}
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("search failed", "query", query, "error", err)
+		reportError(w, err)
+		return "", price.SearchResult{}, err
+	}
+	logging.FromContext(ctx).Info("search complete", "query", query, "offers", len(res.Offers))
+	return query, res, nil
+}
+
+// streamSearch prints each retailer's offers as soon as they arrive. Sorting
+// applies within each retailer's batch.
+func streamSearch(ctx context.Context, args []string, w io.Writer, opts options, searchers map[domain.Retailer]price.Searcher) error {
+	if err := opts.refine.Check(slices.Collect(maps.Keys(searchers))); err != nil {
+		reportError(w, err)
+		return err
+	}
+	query, err := readQuery(args, w)
+	if err != nil {
+		return err
+	}
+
+	ts := render.NewTableStream(w, opts.tableOptions(w))
+	found := false
+	var firstErr error
+	for ev := range price.StreamPrices(ctx, query, searchers) {
+		switch ev.Kind {
+		case price.EventOffers:
+			if offers := refine(ctx, query, ev.Offers, opts); len(offers) > 0 {
+				found = true
+				if err := ts.Offers(offers); err != nil {
+					return err
+				}
+			}
+		case price.EventFailed:
+			if firstErr == nil {
+				firstErr = ev.Err
+			}
+			if err := ts.Failed(ev.Retailer, ev.Err); err != nil {
+				return err
+			}
+		case price.EventDone:
+			if ev.Err != nil {
+				reportError(w, ev.Err)
+				return ev.Err
+			}
+		}
+	}
+	if !found {
+		err := firstErr
+		if err == nil {
+			err = domain.ErrNoResults
 		}
+		reportError(w, err)
 		return err
 	}
+	return nil
+}
 
-	return render.Table(w, offers)
+// readQuery takes the query from args or prompts for it, then checks the API
+// key is configured.
+func readQuery(args []string, w io.Writer) (string, error) {
+	var query string
+	if len(args) > 0 {
+		query = args[0]
+	} else {
+		fmt.Fprint(w, "Enter product: ")
+		if _, err := fmt.Fscanln(os.Stdin, &query); err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+
+	if _, err := config.APIKey(); err != nil {
+		reportError(w, err)
+		return "", err
+	}
+	return query, nil
+}
+
+// refine scores offers for relevance, drops those below the threshold,
+// rates each deal and applies the filter and sort flags. The relevant offers
+// are then added to the price history, after being rated against the prices
+// seen before.
+func refine(ctx context.Context, query string, offers []domain.Offer, opts options) []domain.Offer {
+	offers = relevance.Scorer{Exclude: opts.exclude}.Score(query, offers)
+	offers = relevance.Filter(offers, opts.minRelevance)
+	refined := opts.apply(offers)
+	opts.record(ctx, offers)
+	return refined
+}
+
+// reportError prints a one-line description of err to w, colored if w is a
+// terminal that takes colors.
+func reportError(w io.Writer, err error) {
+	term := render.DetectTerminal(w)
+	switch err {
+	case domain.ErrNoResults:
+		fmt.Fprintln(w, term.Paint(render.Yellow, "No results found."))
+	case domain.ErrNetwork:
+		fmt.Fprintln(w, term.Paint(render.Red, fmt.Sprintf("Network error: %v", err)))
+	default:
+		fmt.Fprintln(w, term.Paint(render.Red, fmt.Sprintf("Error: %v", err)))
+	}
 }
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/grpcapi"
+	"savvyshopper/internal/grpcapi/pricesearchpb"
+	"savvyshopper/internal/price"
+	"savvyshopper/internal/server"
+)
+
+// shutdownTimeout bounds how long in-flight requests get to finish when the
+// server is stopped.
+const shutdownTimeout = 5 * time.Second
+
+// runServe serves the HTTP API, and the gRPC API if --grpc-addr is set, until
+// ctx is cancelled.
+func runServe(ctx context.Context, args []string, w io.Writer, searchersOpt ...map[domain.Retailer]price.Searcher) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	grpcAddr := fs.String("grpc-addr", "", "address to serve the gRPC API on (disabled if empty)")
+	otlpEndpoint := fs.String("otlp-endpoint", "", "export traces over OTLP/HTTP to this URL, e.g. http://localhost:4318")
+	cacheTTL := fs.Duration("cache-ttl", 0, "reuse each retailer's results for the same query for this long, e.g. 5m (disabled if 0)")
+	var log logFlags
+	log.register(fs)
+	var cfgFlags configFlags
+	cfgFlags.register(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	ctx, err := log.withLogger(ctx, os.Stderr)
+	if err != nil {
+		return err
+	}
+	shutdownTracing, err := startTracing(ctx, *otlpEndpoint, w)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+	conf, err := cfgFlags.load(w)
+	if err != nil {
+		return err
+	}
+	if _, err := config.APIKey(); err != nil {
+		reportError(w, err)
+		return err
+	}
+	searchers := conf.searchers(searchersOpt...)
+	handler := server.New(searchers)
+	if *cacheTTL > 0 {
+		handler.EnableCache(*cacheTTL)
+	}
+	if conf.history != nil {
+		handler.EnableForecasts(conf.history.Path())
+	}
+
+	// Requests inherit ctx, and so the logger, but not its cancellation:
+	// Shutdown lets them finish.
+	baseCtx := context.WithoutCancel(ctx)
+	srv := &http.Server{
+		Addr:        *addr,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+	errCh := make(chan error, 2)
+	go func() { errCh <- srv.ListenAndServe() }()
+	fmt.Fprintf(w, "Listening on %s\n", *addr)
+
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			srv.Close()
+			reportError(w, err)
+			return err
+		}
+		grpcSrv := grpc.NewServer()
+		svc := grpcapi.New(conf.products(), searchers)
+		if *cacheTTL > 0 {
+			svc.EnableCache(*cacheTTL)
+		}
+		pricesearchpb.RegisterPriceSearchServer(grpcSrv, svc)
+		go func() { errCh <- grpcSrv.Serve(lis) }()
+		// Watch streams never end on their own, so stop without waiting for them.
+		defer grpcSrv.Stop()
+		fmt.Fprintf(w, "Serving gRPC on %s\n", *grpcAddr)
+	}
+
+	select {
+	case err := <-errCh:
+		reportError(w, err)
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/launch"
+)
+
+// share opens the offer --open picks with opener and copies the URL of the
+// one --copy picks to the clipboard. Offers are numbered from 1 in the order
+// they were listed, and their URLs are used as listed, affiliate tag included.
+// What was done is reported on stderr and the clipboard is set through the
+// terminal, so the results on w are left as they are for pipes and files.
+func (opts options) share(ctx context.Context, w io.Writer, opener launch.Opener, offers []domain.Offer) error {
+	if opts.open > 0 {
+		url, err := pickURL("--open", opts.open, offers)
+		if err == nil {
+			if err = opener.Open(ctx, url); err != nil {
+				err = fmt.Errorf("failed to open %s: %w", url, err)
+			}
+		}
+		if err != nil {
+			reportError(w, err)
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Opened %s\n", url)
+	}
+	if opts.copy > 0 {
+		url, err := pickURL("--copy", opts.copy, offers)
+		if err == nil {
+			term := launch.Terminal()
+			err = launch.Copy(term, url)
+			term.Close()
+		}
+		if err != nil {
+			reportError(w, err)
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Copied %s to the clipboard\n", url)
+	}
+	return nil
+}
+
+// pickURL returns the URL of the nth offer, for flag.
+func pickURL(flag string, n int, offers []domain.Offer) (string, error) {
+	if n > len(offers) {
+		return "", fmt.Errorf("%s %d: only %d offers listed", flag, n, len(offers))
+	}
+	o := offers[n-1]
+	if o.URL == "" {
+		return "", fmt.Errorf("%s %d: %s has no URL", flag, n, o.Title)
+	}
+	return o.URL, nil
+}
@@ -0,0 +1,204 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"savvyshopper/domain"
+	"savvyshopper/internal/config"
+	"savvyshopper/internal/forecast"
+	"savvyshopper/internal/render"
+	"savvyshopper/internal/urlnorm"
+	"savvyshopper/internal/watch"
+)
+
+const watchUsage = "usage: savvyshopper watch add <url|ASIN|item-id>... | list | remove <id>... | refresh"
+
+// runWatch manages the watch list: listings whose prices are recorded on
+// every refresh, so they can be forecast.
+func runWatch(ctx context.Context, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	var log logFlags
+	log.register(fs)
+	var cfgFlags configFlags
+	cfgFlags.register(fs)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if ctx, err = log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	conf, err := cfgFlags.load(w)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		err := errors.New(watchUsage)
+		reportError(w, err)
+		return err
+	}
+	list, err := conf.watches()
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "add" && len(args) > 0:
+		err = watchAdd(ctx, w, conf, list, args)
+	case cmd == "list" && len(args) == 0:
+		err = watchList(w, conf, list)
+	case cmd == "remove" && len(args) > 0:
+		for _, id := range args {
+			if err = list.Remove(id); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = list.Save()
+		}
+	case cmd == "refresh" && len(args) == 0:
+		err = watchRefresh(ctx, w, conf, list)
+	default:
+		err = errors.New(watchUsage)
+	}
+	if err != nil {
+		reportError(w, err)
+	}
+	return err
+}
+
+// watches opens the watch list kept beside the price history.
+func (s settings) watches() (*watch.List, error) {
+	if s.history == nil {
+		return nil, errors.New("watches are kept with the price history, which --no-history turns off")
+	}
+	return watch.Open(watch.PathFor(s.history.Path()))
+}
+
+// watchAdd looks up each listing, watches it and records its current price.
+func watchAdd(ctx context.Context, w io.Writer, conf settings, list *watch.List, refs []string) error {
+	if _, err := config.APIKey(); err != nil {
+		return err
+	}
+	client := conf.products()
+	for _, arg := range refs {
+		ref, err := urlnorm.ParseRef(arg)
+		if err != nil {
+			return err
+		}
+		details, err := client.Details(ctx, ref.Retailer, ref.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		wt := list.Add(watch.Watch{Retailer: ref.Retailer, ProductID: ref.ID, Title: details.Title, URL: details.URL, Added: now})
+		if err := conf.history.Record([]domain.Offer{watchedOffer(wt, details.Offer())}, now); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Watching %s: %s at %s\n", wt.ID, wt.Title, render.Money(details.Price, ref.Retailer))
+	}
+	return list.Save()
+}
+
+// watchList shows the watches with their last recorded prices.
+func watchList(w io.Writer, conf settings, list *watch.List) error {
+	prices := make(map[string]float64)
+	for _, wt := range list.Watches() {
+		if points := conf.history.Points(wt.Key()); len(points) > 0 {
+			prices[wt.ID] = points[len(points)-1].Price
+		}
+	}
+	return render.Watches(w, list.Watches(), prices)
+}
+
+// watchRefresh records the current price of every watched listing. A listing
+// that cannot be looked up is reported and skipped; the first such error is
+// returned once the rest are done.
+func watchRefresh(ctx context.Context, w io.Writer, conf settings, list *watch.List) error {
+	if _, err := config.APIKey(); err != nil {
+		return err
+	}
+	client := conf.products()
+	var firstErr error
+	for _, wt := range list.Watches() {
+		details, err := client.Details(ctx, wt.Retailer, wt.ProductID)
+		if err == nil {
+			err = conf.history.Record([]domain.Offer{watchedOffer(wt, details.Offer())}, time.Now())
+		}
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", wt.ID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s %s\n", wt.ID, render.Money(details.Price, wt.Retailer), wt.Title)
+	}
+	return firstErr
+}
+
+// watchedOffer returns o keyed as the watched listing, whatever retailer
+// and product ID the lookup reported.
+func watchedOffer(wt watch.Watch, o domain.Offer) domain.Offer {
+	o.Retailer, o.ProductID = wt.Retailer, wt.ProductID
+	return o
+}
+
+// runForecast forecasts a watched listing's price from its recorded history.
+func runForecast(ctx context.Context, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("forecast", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	days := fs.Int("days", 14, "forecast this many days ahead")
+	var log logFlags
+	log.register(fs)
+	var cfgFlags configFlags
+	cfgFlags.register(fs)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if _, err = log.withLogger(ctx, os.Stderr); err != nil {
+		return err
+	}
+	conf, err := cfgFlags.load(w)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		err := errors.New("usage: savvyshopper forecast <watch-id>")
+		reportError(w, err)
+		return err
+	}
+
+	list, err := conf.watches()
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	wt, err := list.Get(args[0])
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	f, err := forecast.FromHistory(conf.history.Points(wt.Key()), *days)
+	if errors.Is(err, forecast.ErrTooShort) {
+		err = fmt.Errorf("%w; run savvyshopper watch refresh once a day to build it up", err)
+	}
+	if err != nil {
+		reportError(w, err)
+		return err
+	}
+	title := wt.Title
+	if title == "" {
+		title = wt.Key()
+	}
+	return render.Forecast(w, title, wt.Retailer, f)
+}